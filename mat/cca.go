@@ -0,0 +1,111 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// RandCCA computes the leading canonical correlations between the variables in
+// the columns of the n×p data matrix X and those of the n×q data matrix Y,
+// where the rows of X and Y are paired observations.
+//
+// The columns of X and Y are centered and whitened by the thin QR
+// factorizations Xc = Qx Rx and Yc = Qy Ry, and the canonical correlations are
+// the singular values of the whitened cross-covariance Qxᵀ Qy, which are
+// computed with a randomized singular value decomposition using the parameters
+// chosen by RSVDAuto and rnd as the source of randomness. If rnd is nil, the
+// global rand source is used.
+//
+// RandCCA returns the p×components and q×components canonical weights in the
+// columns of xWeights and yWeights, and the canonical correlations in
+// descending order in corrs. The weights are scaled so that the canonical
+// variables Xc xWeights and Yc yWeights have unit sample variance, and the
+// sample correlation of the i'th columns of the canonical variables is
+// corrs[i].
+//
+// RandCCA returns ErrSingular if the centered X or Y does not have full column
+// rank, which is always the case if n is not greater than p or q, and
+// ErrFailedSVD if the decomposition fails. RandCCA will panic if X and Y do not
+// have the same number of rows, or if components is not in [1, min(p,q)].
+func RandCCA(X, Y Matrix, components int, rnd *rand.Rand) (xWeights, yWeights *Dense, corrs []float64, err error) {
+	n, p := X.Dims()
+	ny, q := Y.Dims()
+	if n != ny {
+		panic(ErrShape)
+	}
+	if components < 1 || min(p, q) < components {
+		panic(ErrShape)
+	}
+	if n <= max(p, q) {
+		return nil, nil, nil, ErrSingular
+	}
+
+	var xc, yc Dense
+	xc.CenterColumns(X)
+	yc.CenterColumns(Y)
+	qx, rx, ok := whitenColumns(&xc)
+	if !ok {
+		return nil, nil, nil, ErrSingular
+	}
+	qy, ry, ok := whitenColumns(&yc)
+	if !ok {
+		return nil, nil, nil, ErrSingular
+	}
+
+	var c Dense
+	c.Mul(qx.T(), qy)
+	var rsvd RSVD
+	if !rsvd.Factorize(&c, components, withRand(rnd), RSVDAuto()) {
+		return nil, nil, nil, ErrFailedSVD
+	}
+	corrs = rsvd.Values(nil)
+	for i, v := range corrs {
+		// Rounding may give correlations slightly above one.
+		corrs[i] = math.Min(v, 1)
+	}
+
+	// The canonical weights are Rx⁻¹ U and Ry⁻¹ V, scaled to
+	// give canonical variables of unit sample variance.
+	f := math.Sqrt(float64(n - 1))
+	xWeights, yWeights = &Dense{}, &Dense{}
+	rsvd.UTo(xWeights)
+	rsvd.VTo(yWeights)
+	for _, w := range []struct {
+		r, dst *Dense
+	}{{rx, xWeights}, {ry, yWeights}} {
+		blas64.Trsm(blas.Left, blas.NoTrans, f, blas64.Triangular{
+			Uplo:   blas.Upper,
+			Diag:   blas.NonUnit,
+			N:      w.r.mat.Rows,
+			Data:   w.r.mat.Data,
+			Stride: w.r.mat.Stride,
+		}, w.dst.mat)
+	}
+	return xWeights, yWeights, corrs, nil
+}
+
+// whitenColumns returns the thin QR factorization a = q r of the n×k matrix a
+// with k < n, and whether r is numerically non-singular.
+func whitenColumns(a *Dense) (q, r *Dense, ok bool) {
+	m, k := a.Dims()
+	q, r = thinQR(a)
+	var rmax float64
+	for i := 0; i < k; i++ {
+		rmax = math.Max(rmax, math.Abs(r.at(i, i)))
+	}
+	tol := float64(m) * (1.0 / (1 << 53)) * rmax
+	for i := 0; i < k; i++ {
+		if math.Abs(r.at(i, i)) <= tol {
+			return nil, nil, false
+		}
+	}
+	return q, r, true
+}
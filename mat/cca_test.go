@@ -0,0 +1,97 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestRandCCA(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, p, q, latent = 200, 8, 6, 2
+
+	// X and Y share two latent variables with different loadings.
+	z := NewRandomNormalDense(n, latent, rnd)
+	var x, y Dense
+	x.Mul(z, NewRandomNormalDense(latent, p, rnd))
+	x.Add(&x, NewRandomNormalDense(n, p, rnd))
+	y.Mul(z, NewRandomNormalDense(latent, q, rnd))
+	y.Add(&y, NewRandomNormalDense(n, q, rnd))
+
+	const components = 3
+	xw, yw, corrs, err := RandCCA(&x, &y, components, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The correlations are the singular values of the whitened
+	// cross-covariance computed deterministically.
+	var xc, yc Dense
+	xc.CenterColumns(&x)
+	yc.CenterColumns(&y)
+	qx, _ := thinQR(&xc)
+	qy, _ := thinQR(&yc)
+	var c Dense
+	c.Mul(qx.T(), qy)
+	var svd SVD
+	if !svd.Factorize(&c, SVDNone) {
+		t.Fatal("unexpected SVD failure")
+	}
+	if want := svd.Values(nil)[:components]; !floats.EqualApprox(corrs, want, 1e-10) {
+		t.Errorf("unexpected correlations: got:%v want:%v", corrs, want)
+	}
+
+	// The canonical variables have unit variance and the
+	// canonical correlations, and are otherwise uncorrelated.
+	var u, v, uv, uu, vv Dense
+	u.Mul(&xc, xw)
+	v.Mul(&yc, yw)
+	uv.Mul(u.T(), &v)
+	uu.Mul(u.T(), &u)
+	vv.Mul(v.T(), &v)
+	for i := 0; i < components; i++ {
+		for j := 0; j < components; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if got := uu.At(i, j) / (n - 1); math.Abs(got-want) > 1e-10 {
+				t.Errorf("unexpected X canonical covariance at (%d,%d): got:%v want:%v", i, j, got, want)
+			}
+			if got := vv.At(i, j) / (n - 1); math.Abs(got-want) > 1e-10 {
+				t.Errorf("unexpected Y canonical covariance at (%d,%d): got:%v want:%v", i, j, got, want)
+			}
+			if i == j {
+				want = corrs[i]
+			}
+			if got := uv.At(i, j) / (n - 1); math.Abs(got-want) > 1e-10 {
+				t.Errorf("unexpected cross covariance at (%d,%d): got:%v want:%v", i, j, got, want)
+			}
+		}
+	}
+
+	if _, _, _, err := RandCCA(x.Slice(0, p, 0, p), y.Slice(0, p, 0, q), 2, rnd); err != ErrSingular {
+		t.Errorf("unexpected error for too few observations: got:%v want:%v", err, ErrSingular)
+	}
+	dup := DenseCopyOf(&x)
+	for i := 0; i < n; i++ {
+		dup.Set(i, 1, 2*dup.At(i, 0))
+	}
+	if _, _, _, err := RandCCA(dup, &y, 2, rnd); err != ErrSingular {
+		t.Errorf("unexpected error for rank deficient X: got:%v want:%v", err, ErrSingular)
+	}
+	if p, _ := panics(func() { RandCCA(&x, y.Slice(0, n-1, 0, q), 2, rnd) }); !p {
+		t.Error("expected panic for mismatched observations")
+	}
+	if p, _ := panics(func() { RandCCA(&x, &y, q+1, rnd) }); !p {
+		t.Error("expected panic for too many components")
+	}
+}
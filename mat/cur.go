@@ -0,0 +1,236 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// CURSelection specifies how the rows and columns of a CUR decomposition are
+// selected.
+type CURSelection int
+
+const (
+	// CURDEIM selects the rows and columns deterministically from
+	// the leading singular vectors by the discrete empirical
+	// interpolation method of Sorensen and Embree, which greedily
+	// picks the index of largest interpolation residual of each
+	// successive singular vector.
+	CURDEIM CURSelection = iota
+
+	// CURLeverage samples the rows and columns without replacement
+	// with probabilities proportional to their rank-k leverage
+	// scores, the squared row norms of the leading singular vectors.
+	CURLeverage
+)
+
+// CUR is a type for creating and using the CUR decomposition of a matrix,
+//  A ≈ C U R,
+// where C = A[:, J] holds k columns of A, R = A[I, :] holds k rows of A and
+// U is the k×k matrix that minimizes the Frobenius norm error for the chosen
+// C and R. Unlike the factors of the singular value decomposition, C and R
+// are actual columns and rows of A, so they retain its sparsity and the
+// meaning of its entries.
+type CUR struct {
+	rows, cols []int
+	c, u, r    *Dense
+}
+
+// Factorize computes the rank-k CUR decomposition of the m×n matrix A. The
+// leading k singular vectors of A are computed by RSVD.Factorize with an
+// oversampling of 10 and one power iteration, using rnd as the source of
+// randomness, and the rows and columns are selected from them as specified by
+// sel. If rnd is nil, the global rand source is used. The middle factor is
+//  U = C⁺ A R⁺,
+// computed from QR factorizations of C and Rᵀ.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if the selected rows or columns are numerically dependent, which is
+// the case if the rank of A is less than k, or if the singular value
+// decomposition fails. If the decomposition failed, routines that require a
+// successful factorization will panic. Factorize will panic if k is not in
+// [1, min(m,n)] or if sel is not a known CURSelection.
+func (cur *CUR) Factorize(A Matrix, k int, sel CURSelection, rnd *rand.Rand) (ok bool) {
+	m, n := A.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	if sel != CURDEIM && sel != CURLeverage {
+		panic("mat: unknown CUR selection")
+	}
+	cur.rows, cur.cols = nil, nil
+	cur.c, cur.u, cur.r = nil, nil, nil
+
+	var rsvd RSVD
+	if !rsvd.Factorize(A, k, withRand(rnd), RSVDOversample(10), RSVDPowerIter(1)) {
+		return false
+	}
+	var uk, vk Dense
+	rsvd.UTo(&uk)
+	rsvd.VTo(&vk)
+	var rows, cols []int
+	switch sel {
+	case CURDEIM:
+		rows, cols = deim(&uk), deim(&vk)
+	case CURLeverage:
+		rows = weightedSample(leverageScores(&uk), k, rnd)
+		cols = weightedSample(leverageScores(&vk), k, rnd)
+	}
+
+	c := NewDense(m, k, nil)
+	for i := 0; i < m; i++ {
+		row := c.rawRowView(i)
+		for j, cj := range cols {
+			row[j] = A.At(i, cj)
+		}
+	}
+	r := NewDense(k, n, nil)
+	for i, ri := range rows {
+		row := r.rawRowView(i)
+		for j := range row {
+			row[j] = A.At(ri, j)
+		}
+	}
+
+	// [U] = [C⁺ × A × R⁺] = k × k
+	var qc QR
+	qc.Factorize(c)
+	var ca Dense
+	if err := qc.SolveTo(&ca, false, A); err != nil {
+		return false
+	}
+	var qr QR
+	qr.Factorize(r.T())
+	var ut Dense
+	if err := qr.SolveTo(&ut, false, ca.T()); err != nil {
+		return false
+	}
+
+	cur.rows, cur.cols = rows, cols
+	cur.c, cur.u, cur.r = c, DenseCopyOf(ut.T()), r
+	return true
+}
+
+// deim returns the indices of the rows of the n×k matrix v with orthonormal
+// columns selected by the discrete empirical interpolation method.
+func deim(v *Dense) []int {
+	n, k := v.Dims()
+	idx := make([]int, 0, k)
+	res := make([]float64, n)
+	for j := 0; j < k; j++ {
+		for i := range res {
+			res[i] = v.at(i, j)
+		}
+		if j > 0 {
+			// Subtract the interpolant of column j from the
+			// columns 0..j-1 at the selected rows:
+			//  res = v_j - V_j (V_j[p, :])⁻¹ v_j[p]
+			vp := NewDense(j, j, nil)
+			b := NewVecDense(j, nil)
+			for a, p := range idx {
+				for c := 0; c < j; c++ {
+					vp.set(a, c, v.at(p, c))
+				}
+				b.SetVec(a, v.at(p, j))
+			}
+			var coef VecDense
+			if err := coef.SolveVec(vp, b); err == nil {
+				for i := range res {
+					for c := 0; c < j; c++ {
+						res[i] -= v.at(i, c) * coef.AtVec(c)
+					}
+				}
+			}
+		}
+		p, best := -1, -1.0
+		for i, r := range res {
+			if containsInt(idx, i) {
+				continue
+			}
+			if a := math.Abs(r); a > best {
+				p, best = i, a
+			}
+		}
+		idx = append(idx, p)
+	}
+	return idx
+}
+
+// containsInt returns whether s contains v.
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (cur *CUR) succFact() bool {
+	return cur.u != nil
+}
+
+// Rows returns the indices of the rows of A selected for R, in the order of
+// the rows of R.
+//
+// Rows will panic if the receiver does not contain a successful factorization.
+func (cur *CUR) Rows() []int {
+	if !cur.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), cur.rows...)
+}
+
+// Columns returns the indices of the columns of A selected for C, in the
+// order of the columns of C.
+//
+// Columns will panic if the receiver does not contain a successful factorization.
+func (cur *CUR) Columns() []int {
+	if !cur.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), cur.cols...)
+}
+
+// CTo extracts the m×k matrix C of selected columns into dst.
+//
+// If dst is empty, CTo will resize dst to be m×k. When dst is non-empty, CTo
+// will panic if dst is not m×k. CTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) CTo(dst *Dense) {
+	if !cur.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(cur.c.Dims())
+	dst.Copy(cur.c)
+}
+
+// UTo extracts the k×k middle factor U into dst.
+//
+// If dst is empty, UTo will resize dst to be k×k. When dst is non-empty, UTo
+// will panic if dst is not k×k. UTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) UTo(dst *Dense) {
+	if !cur.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(cur.u.Dims())
+	dst.Copy(cur.u)
+}
+
+// RTo extracts the k×n matrix R of selected rows into dst.
+//
+// If dst is empty, RTo will resize dst to be k×n. When dst is non-empty, RTo
+// will panic if dst is not k×n. RTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) RTo(dst *Dense) {
+	if !cur.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(cur.r.Dims())
+	dst.Copy(cur.r)
+}
@@ -0,0 +1,84 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCUR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 30, n: 20, k: 4},
+		{m: 20, n: 35, k: 6},
+		{m: 8, n: 8, k: 8},
+	} {
+		a := randLowRank(test.m, test.n, test.k, rnd)
+		for _, sel := range []CURSelection{CURDEIM, CURLeverage} {
+			var cur CUR
+			if !cur.Factorize(a, test.k, sel, rnd) {
+				t.Fatalf("%d×%d sel=%d: unexpected factorization failure", test.m, test.n, sel)
+			}
+			rows, cols := cur.Rows(), cur.Columns()
+			if len(rows) != test.k || !isIndexSet(rows, test.m) {
+				t.Errorf("%d×%d sel=%d: invalid row selection %v", test.m, test.n, sel, rows)
+			}
+			if len(cols) != test.k || !isIndexSet(cols, test.n) {
+				t.Errorf("%d×%d sel=%d: invalid column selection %v", test.m, test.n, sel, cols)
+			}
+
+			var c, u, r Dense
+			cur.CTo(&c)
+			cur.UTo(&u)
+			cur.RTo(&r)
+			for j, cj := range cols {
+				for i := 0; i < test.m; i++ {
+					if c.At(i, j) != a.At(i, cj) {
+						t.Fatalf("%d×%d sel=%d: C is not a column selection of A", test.m, test.n, sel)
+					}
+				}
+			}
+			for i, ri := range rows {
+				for j := 0; j < test.n; j++ {
+					if r.At(i, j) != a.At(ri, j) {
+						t.Fatalf("%d×%d sel=%d: R is not a row selection of A", test.m, test.n, sel)
+					}
+				}
+			}
+			var got Dense
+			got.Product(&c, &u, &r)
+			if !EqualApprox(&got, a, 1e-8) {
+				t.Errorf("%d×%d sel=%d: unexpected reconstruction", test.m, test.n, sel)
+			}
+		}
+	}
+
+	// DEIM selects the support of sparse singular vectors.
+	a := NewDense(10, 10, nil)
+	a.Set(2, 7, 5)
+	a.Set(6, 3, 2)
+	var cur CUR
+	if !cur.Factorize(a, 2, CURDEIM, rnd) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if rows, cols := cur.Rows(), cur.Columns(); rows[0] != 2 || rows[1] != 6 || cols[0] != 7 || cols[1] != 3 {
+		t.Errorf("unexpected selection for sparse matrix: rows %v cols %v", rows, cols)
+	}
+
+	if cur.Factorize(randLowRank(10, 8, 2, rnd), 4, CURDEIM, rnd) {
+		t.Error("unexpected success for rank deficient matrix")
+	}
+	if p, _ := panics(func() { cur.Factorize(a, 11, CURDEIM, rnd) }); !p {
+		t.Error("expected panic for rank greater than min(m,n)")
+	}
+	if p, _ := panics(func() { cur.Factorize(a, 2, CURSelection(-1), rnd) }); !p {
+		t.Error("expected panic for unknown selection")
+	}
+}
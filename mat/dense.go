@@ -5,6 +5,8 @@
 package mat
 
 import (
+	"sort"
+
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
 )
@@ -311,6 +313,24 @@ func (m *Dense) DiagView() Diagonal {
 	}
 }
 
+// SetDiag sets the receiver to the diagonal matrix with the elements of d on
+// its diagonal, zeroing the off-diagonal elements. If the receiver is empty,
+// it is resized to be len(d)×len(d). SetDiag will panic if the receiver is
+// non-empty and the length of its diagonal, min(r,c), is not len(d).
+func (m *Dense) SetDiag(d []float64) {
+	if m.IsEmpty() {
+		m.reuseAsZeroed(len(d), len(d))
+	} else {
+		if min(m.mat.Rows, m.mat.Cols) != len(d) {
+			panic(ErrShape)
+		}
+		m.Zero()
+	}
+	for i, v := range d {
+		m.mat.Data[i*m.mat.Stride+i] = v
+	}
+}
+
 // Slice returns a new Matrix that shares backing data with the receiver.
 // The returned matrix starts at {i,j} of the receiver and extends k-i rows
 // and l-j columns. The final row in the resulting matrix is k-1 and the
@@ -338,6 +358,30 @@ func (m *Dense) slice(i, k, j, l int) *Dense {
 	return &t
 }
 
+// SubMatrixCopy copies the block of the receiver starting at {i,j} and
+// extending k-i rows and l-j columns into dst. Unlike Slice, the copy does
+// not share backing data with the receiver, so later changes to either
+// matrix do not affect the other.
+//
+// If dst is empty, SubMatrixCopy will resize dst to be (k-i)×(l-j). When dst
+// is non-empty, SubMatrixCopy will panic if dst is not (k-i)×(l-j).
+// SubMatrixCopy panics with ErrIndexOutOfRange if the block is outside the
+// receiver, and with ErrZeroLength if the block is empty.
+func (m *Dense) SubMatrixCopy(dst *Dense, i, k, j, l int) {
+	r, c := m.Dims()
+	if i < 0 || r <= i || j < 0 || c <= j || k < i || r < k || l < j || c < l {
+		if i == k || j == l {
+			panic(ErrZeroLength)
+		}
+		panic(ErrIndexOutOfRange)
+	}
+	if i == k || j == l {
+		panic(ErrZeroLength)
+	}
+	dst.reuseAsNonZeroed(k-i, l-j)
+	dst.Copy(m.slice(i, k, j, l))
+}
+
 // Grow returns the receiver expanded by r rows and c columns. If the dimensions
 // of the expanded matrix are outside the capacities of the receiver a new
 // allocation is made, otherwise not. Note the receiver itself is not modified
@@ -571,6 +615,151 @@ func (m *Dense) Augment(a, b Matrix) {
 	w.Copy(b)
 }
 
+// Tile places rowReps×colReps copies of a into the receiver, so that the
+// receiver is a (rowReps·ar)×(colReps·ac) block matrix with every block equal
+// to a. Tile will panic if either repetition count is not positive, if a is
+// the receiver, or if the tiled matrix is not the same shape as a non-empty
+// receiver.
+func (m *Dense) Tile(a Matrix, rowReps, colReps int) {
+	if rowReps <= 0 || colReps <= 0 {
+		panic("mat: non-positive repetition count")
+	}
+	if m == a {
+		panic(ErrShape)
+	}
+	ar, ac := a.Dims()
+
+	m.reuseAsNonZeroed(rowReps*ar, colReps*ac)
+
+	w := m.slice(0, ar, 0, ac)
+	w.Copy(a)
+	for j := 1; j < colReps; j++ {
+		m.slice(0, ar, j*ac, (j+1)*ac).Copy(w)
+	}
+	w = m.slice(0, ar, 0, colReps*ac)
+	for i := 1; i < rowReps; i++ {
+		m.slice(i*ar, (i+1)*ar, 0, colReps*ac).Copy(w)
+	}
+}
+
+// BlockDiag places the blocks along the diagonal of the receiver in order,
+// with all elements outside the blocks set to zero. The receiver is sized to
+// be (Σ rᵢ)×(Σ cᵢ) where rᵢ×cᵢ is the shape of the iᵗʰ block. BlockDiag will
+// panic if no blocks are provided, if any block is the receiver, or if the
+// assembled matrix is not the same shape as a non-empty receiver.
+func (m *Dense) BlockDiag(blocks ...Matrix) {
+	if len(blocks) == 0 {
+		panic(ErrZeroLength)
+	}
+	var r, c int
+	for _, b := range blocks {
+		if m == b {
+			panic(ErrShape)
+		}
+		br, bc := b.Dims()
+		r += br
+		c += bc
+	}
+
+	m.reuseAsZeroed(r, c)
+
+	var i, j int
+	for _, b := range blocks {
+		br, bc := b.Dims()
+		w := m.slice(i, i+br, j, j+bc)
+		w.Copy(b)
+		i += br
+		j += bc
+	}
+}
+
+// DropColumns removes the columns of the receiver with the given indices,
+// moving the remaining columns, in order, into the leading columns of the
+// receiver's storage and reducing its number of columns. The indices need
+// not be sorted and repeated indices are dropped once. DropColumns will panic
+// if an index is out of range or if all columns would be removed.
+//
+// DropColumns modifies the backing data of the receiver, which is shared with
+// any matrix the receiver is a view of.
+func (m *Dense) DropColumns(cols []int) {
+	drop := sortedUnique(cols)
+	if len(drop) == 0 {
+		return
+	}
+	if drop[0] < 0 || drop[len(drop)-1] >= m.mat.Cols {
+		panic(ErrColAccess)
+	}
+	if len(drop) == m.mat.Cols {
+		panic(ErrZeroLength)
+	}
+	for i := 0; i < m.mat.Rows; i++ {
+		row := m.rawRowView(i)
+		compact(row, drop)
+	}
+	m.mat.Cols -= len(drop)
+}
+
+// DropRows removes the rows of the receiver with the given indices, moving
+// the remaining rows, in order, into the leading rows of the receiver's
+// storage and reducing its number of rows. The indices need not be sorted
+// and repeated indices are dropped once. DropRows will panic if an index is
+// out of range or if all rows would be removed.
+//
+// DropRows modifies the backing data of the receiver, which is shared with
+// any matrix the receiver is a view of.
+func (m *Dense) DropRows(rows []int) {
+	drop := sortedUnique(rows)
+	if len(drop) == 0 {
+		return
+	}
+	if drop[0] < 0 || drop[len(drop)-1] >= m.mat.Rows {
+		panic(ErrRowAccess)
+	}
+	if len(drop) == m.mat.Rows {
+		panic(ErrZeroLength)
+	}
+	var w, d int
+	for i := 0; i < m.mat.Rows; i++ {
+		if d < len(drop) && drop[d] == i {
+			d++
+			continue
+		}
+		if w != i {
+			copy(m.rawRowView(w), m.rawRowView(i))
+		}
+		w++
+	}
+	m.mat.Rows -= len(drop)
+}
+
+// sortedUnique returns a sorted copy of idx with duplicates removed.
+func sortedUnique(idx []int) []int {
+	s := append([]int(nil), idx...)
+	sort.Ints(s)
+	var n int
+	for i, v := range s {
+		if i == 0 || v != s[n-1] {
+			s[n] = v
+			n++
+		}
+	}
+	return s[:n]
+}
+
+// compact moves the elements of s whose indices are not in the sorted
+// set drop to the front of s, preserving their order.
+func compact(s []float64, drop []int) {
+	var w, d int
+	for i, v := range s {
+		if d < len(drop) && drop[d] == i {
+			d++
+			continue
+		}
+		s[w] = v
+		w++
+	}
+}
+
 // Trace returns the trace of the matrix. The matrix must be square or Trace
 // will panic.
 func (m *Dense) Trace() float64 {
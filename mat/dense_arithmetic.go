@@ -767,6 +767,203 @@ func (m *Dense) Apply(fn func(i, j int, v float64) float64, a Matrix) {
 	}
 }
 
+// ScaleRows multiplies the rows of a by the elements of d, placing the result
+// in the receiver. This is equivalent to
+//  m = diag(d) * a
+// without forming the diagonal matrix. ScaleRows will panic if the length of
+// d does not equal the number of rows of a.
+func (m *Dense) ScaleRows(d []float64, a Matrix) {
+	ar, ac := a.Dims()
+	if len(d) != ar {
+		panic(ErrShape)
+	}
+	m.reuseAsNonZeroed(ar, ac)
+	if m.scaleNeedsWorkspace(a) {
+		var restore func()
+		m, restore = m.isolatedWorkspace(a)
+		defer restore()
+	}
+	m.Copy(a)
+	for i, f := range d {
+		row := m.rawRowView(i)
+		for j := range row {
+			row[j] *= f
+		}
+	}
+}
+
+// ScaleCols multiplies the columns of a by the elements of d, placing the
+// result in the receiver. This is equivalent to
+//  m = a * diag(d)
+// without forming the diagonal matrix. ScaleCols will panic if the length of
+// d does not equal the number of columns of a.
+func (m *Dense) ScaleCols(d []float64, a Matrix) {
+	ar, ac := a.Dims()
+	if len(d) != ac {
+		panic(ErrShape)
+	}
+	m.reuseAsNonZeroed(ar, ac)
+	if m.scaleNeedsWorkspace(a) {
+		var restore func()
+		m, restore = m.isolatedWorkspace(a)
+		defer restore()
+	}
+	m.Copy(a)
+	for i := 0; i < ar; i++ {
+		row := m.rawRowView(i)
+		for j, f := range d {
+			row[j] *= f
+		}
+	}
+}
+
+// scaleNeedsWorkspace returns whether ScaleRows and ScaleCols must work in an
+// isolated workspace because the receiver is the transpose of a. Scaling the
+// receiver in place needs no workspace. scaleNeedsWorkspace will panic if the
+// receiver otherwise overlaps a.
+func (m *Dense) scaleNeedsWorkspace(a Matrix) bool {
+	aU, aTrans := untransposeExtract(a)
+	if rm, ok := aU.(*Dense); ok {
+		if m == aU {
+			return aTrans
+		}
+		return m.checkOverlap(rm.mat)
+	}
+	m.checkOverlapMatrix(a)
+	return false
+}
+
+// CenterColumns subtracts from each column of a its mean, placing the result
+// in the receiver, and returns the column means. The means can be used to
+// center further observations in the same way as the columns of a.
+func (m *Dense) CenterColumns(a Matrix) (means []float64) {
+	ar, ac := a.Dims()
+	m.reuseAsNonZeroed(ar, ac)
+	m.Copy(a)
+	means = make([]float64, ac)
+	for i := 0; i < ar; i++ {
+		for j, v := range m.rawRowView(i) {
+			means[j] += v
+		}
+	}
+	for j := range means {
+		means[j] /= float64(ar)
+	}
+	for i := 0; i < ar; i++ {
+		row := m.rawRowView(i)
+		for j := range row {
+			row[j] -= means[j]
+		}
+	}
+	return means
+}
+
+// CenterRows subtracts from each row of a its mean, placing the result
+// in the receiver, and returns the row means.
+func (m *Dense) CenterRows(a Matrix) (means []float64) {
+	ar, ac := a.Dims()
+	m.reuseAsNonZeroed(ar, ac)
+	m.Copy(a)
+	means = make([]float64, ar)
+	for i := range means {
+		row := m.rawRowView(i)
+		var sum float64
+		for _, v := range row {
+			sum += v
+		}
+		means[i] = sum / float64(ac)
+		for j := range row {
+			row[j] -= means[i]
+		}
+	}
+	return means
+}
+
+// ApplyRowwise calls fn for each row of the receiver in order, with i the
+// index of the row and row a slice referencing the elements of the row in
+// the receiver's storage. Changes made by fn to the elements of row are
+// reflected in the receiver. The row slice must not be retained after fn
+// returns.
+func (m *Dense) ApplyRowwise(fn func(i int, row []float64)) {
+	for i := 0; i < m.mat.Rows; i++ {
+		fn(i, m.rawRowView(i))
+	}
+}
+
+// ApplyColumnwise calls fn for each column of the receiver in order, with j
+// the index of the column and col a contiguous slice holding the elements of
+// the column. Since the columns of a Dense are not contiguous in memory, col
+// is a copy of the column in a reused buffer, and after fn returns the
+// elements of col are written back to the receiver. The col slice must not
+// be retained after fn returns.
+func (m *Dense) ApplyColumnwise(fn func(j int, col []float64)) {
+	r, c := m.mat.Rows, m.mat.Cols
+	if r == 0 || c == 0 {
+		return
+	}
+	col := getFloats(r, false)
+	defer putFloats(col)
+	buf := blas64.Vector{N: r, Inc: 1, Data: col}
+	for j := 0; j < c; j++ {
+		v := blas64.Vector{N: r, Inc: m.mat.Stride, Data: m.mat.Data[j:]}
+		blas64.Copy(v, buf)
+		fn(j, col)
+		blas64.Copy(buf, v)
+	}
+}
+
+// NormalizeColumns scales each column of the receiver to have unit norm.
+// Columns with zero norm are left unchanged.
+//
+// Valid norms are:
+//    1 - The sum of the absolute values of the elements.
+//    2 - The Euclidean norm.
+//  Inf - The maximum absolute value of the elements.
+// NormalizeColumns will panic with ErrNormOrder if an illegal norm order is
+// specified.
+func (m *Dense) NormalizeColumns(norm float64) {
+	checkVectorNorm(norm)
+	for j := 0; j < m.mat.Cols; j++ {
+		normalizeVec(blas64.Vector{N: m.mat.Rows, Inc: m.mat.Stride, Data: m.mat.Data[j:]}, norm)
+	}
+}
+
+// NormalizeRows scales each row of the receiver to have unit norm.
+// Rows with zero norm are left unchanged. The valid norms are those of
+// NormalizeColumns. NormalizeRows will panic with ErrNormOrder if an
+// illegal norm order is specified.
+func (m *Dense) NormalizeRows(norm float64) {
+	checkVectorNorm(norm)
+	for i := 0; i < m.mat.Rows; i++ {
+		normalizeVec(blas64.Vector{N: m.mat.Cols, Inc: 1, Data: m.rawRowView(i)}, norm)
+	}
+}
+
+func checkVectorNorm(norm float64) {
+	if norm != 1 && norm != 2 && !math.IsInf(norm, 1) {
+		panic(ErrNormOrder)
+	}
+}
+
+// normalizeVec scales v to unit norm unless it is zero.
+func normalizeVec(v blas64.Vector, norm float64) {
+	if v.N == 0 {
+		return
+	}
+	var n float64
+	switch norm {
+	case 1:
+		n = blas64.Asum(v)
+	case 2:
+		n = blas64.Nrm2(v)
+	default:
+		n = math.Abs(v.Data[blas64.Iamax(v)*v.Inc])
+	}
+	if n != 0 {
+		blas64.Scal(1/n, v)
+	}
+}
+
 // RankOne performs a rank-one update to the matrix a with the vectors x and
 // y, where x and y are treated as column vectors. The result is stored in the
 // receiver. The Outer method can be used instead of RankOne if a is not needed.
@@ -419,6 +419,34 @@ func TestDenseDiagView(t *testing.T) {
 	}
 }
 
+func TestDenseSetDiag(t *testing.T) {
+	t.Parallel()
+	var m Dense
+	m.SetDiag([]float64{1, 2, 3})
+	want := NewDense(3, 3, []float64{1, 0, 0, 0, 2, 0, 0, 0, 3})
+	if !Equal(&m, want) {
+		t.Errorf("unexpected matrix for empty receiver:\n%v", Formatted(&m))
+	}
+
+	a := NewRandomNormalDense(5, 6, rand.New(rand.NewSource(1)))
+	view := a.Slice(1, 5, 1, 3).(*Dense)
+	view.SetDiag([]float64{-1, 4})
+	want = NewDense(4, 2, []float64{-1, 0, 0, 4, 0, 0, 0, 0})
+	if !Equal(view, want) {
+		t.Errorf("unexpected matrix for view:\n%v", Formatted(view))
+	}
+	if a.At(0, 0) == 0 || a.At(1, 3) == 0 {
+		t.Error("elements outside the view modified")
+	}
+	if got := Diag(nil, view); !reflect.DeepEqual(got, []float64{-1, 4}) {
+		t.Errorf("unexpected diagonal: got:%v", got)
+	}
+
+	if p, _ := panics(func() { m.SetDiag([]float64{1, 2}) }); !p {
+		t.Error("expected panic for mismatched diagonal length")
+	}
+}
+
 func TestDenseGrow(t *testing.T) {
 	t.Parallel()
 	m := &Dense{}
@@ -1542,6 +1570,277 @@ func TestDenseApply(t *testing.T) {
 	}
 }
 
+func TestDenseSubMatrixCopy(t *testing.T) {
+	t.Parallel()
+	a := NewDense(4, 5, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = float64(i)
+	}
+	for _, test := range []struct {
+		i, k, j, l int
+	}{
+		{0, 4, 0, 5},
+		{1, 3, 2, 5},
+		{3, 4, 0, 1},
+		{0, 2, 4, 5},
+	} {
+		want := DenseCopyOf(a.Slice(test.i, test.k, test.j, test.l))
+		var got Dense
+		a.SubMatrixCopy(&got, test.i, test.k, test.j, test.l)
+		if !Equal(&got, want) {
+			t.Errorf("unexpected copy of [%d:%d, %d:%d]", test.i, test.k, test.j, test.l)
+		}
+		// The copy must not share storage with the receiver.
+		got.Set(0, 0, -1)
+		if a.At(test.i, test.j) == -1 {
+			t.Errorf("copy of [%d:%d, %d:%d] shares storage", test.i, test.k, test.j, test.l)
+		}
+
+		// Copying into a correctly sized destination reuses it.
+		r, c := want.Dims()
+		dst := NewDense(r, c, nil)
+		a.SubMatrixCopy(dst, test.i, test.k, test.j, test.l)
+		if !Equal(dst, want) {
+			t.Errorf("unexpected copy into sized destination of [%d:%d, %d:%d]", test.i, test.k, test.j, test.l)
+		}
+	}
+
+	for _, test := range []struct {
+		i, k, j, l int
+	}{
+		{-1, 2, 0, 2},
+		{0, 5, 0, 2},
+		{0, 2, 3, 6},
+		{2, 1, 0, 2},
+		{1, 1, 0, 2},
+	} {
+		if p, _ := panics(func() { a.SubMatrixCopy(&Dense{}, test.i, test.k, test.j, test.l) }); !p {
+			t.Errorf("expected panic for [%d:%d, %d:%d]", test.i, test.k, test.j, test.l)
+		}
+	}
+	if p, _ := panics(func() { a.SubMatrixCopy(NewDense(1, 1, nil), 0, 2, 0, 2) }); !p {
+		t.Error("expected panic for mismatched destination")
+	}
+}
+
+func TestDenseScaleRowsCols(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const r, c = 5, 4
+	d1 := []float64{2, -1, 0, 0.5, 3}
+	d2 := []float64{1, 4, -2, 0.25}
+	for _, a := range []Matrix{
+		NewRandomNormalDense(r, c, rnd),
+		NewRandomNormalDense(c, r, rnd).T(),
+		randBandDense(r, c, 1, 1, rnd),
+	} {
+		var want, got Dense
+		want.Mul(NewDiagDense(r, d1), a)
+		got.ScaleRows(d1, a)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected row scaling of %T", a)
+		}
+		want.Reset()
+		got.Reset()
+		want.Mul(a, NewDiagDense(c, d2))
+		got.ScaleCols(d2, a)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected column scaling of %T", a)
+		}
+	}
+
+	// Scaling in place.
+	a := NewRandomNormalDense(r, c, rnd)
+	var want Dense
+	want.Product(NewDiagDense(r, d1), a, NewDiagDense(c, d2))
+	a.ScaleRows(d1, a)
+	a.ScaleCols(d2, a)
+	if !EqualApprox(a, &want, 1e-14) {
+		t.Error("unexpected in place scaling")
+	}
+
+	// Scaling the transpose of the receiver.
+	s := NewRandomNormalDense(c, c, rnd)
+	want.Reset()
+	want.Mul(NewDiagDense(c, d2), s.T())
+	s.ScaleRows(d2, s.T())
+	if !EqualApprox(s, &want, 1e-14) {
+		t.Error("unexpected row scaling of the transposed receiver")
+	}
+	s = NewRandomNormalDense(c, c, rnd)
+	want.Reset()
+	want.Mul(s.T(), NewDiagDense(c, d2))
+	s.ScaleCols(d2, s.T())
+	if !EqualApprox(s, &want, 1e-14) {
+		t.Error("unexpected column scaling of the transposed receiver")
+	}
+
+	// Partially overlapping the receiver.
+	b := NewRandomNormalDense(r+1, c, rnd)
+	dst := b.Slice(0, r, 0, c).(*Dense)
+	src := b.Slice(1, r+1, 0, c)
+	if p, _ := panics(func() { dst.ScaleRows(d1, src) }); !p {
+		t.Error("expected panic for overlapping row scaling")
+	}
+	if p, _ := panics(func() { dst.ScaleCols(d2, src) }); !p {
+		t.Error("expected panic for overlapping column scaling")
+	}
+
+	if p, _ := panics(func() { a.ScaleRows(d2, a) }); !p {
+		t.Error("expected panic for mismatched row scales")
+	}
+	if p, _ := panics(func() { a.ScaleCols(d1, a) }); !p {
+		t.Error("expected panic for mismatched column scales")
+	}
+}
+
+func TestDenseApplyRowColumnwise(t *testing.T) {
+	t.Parallel()
+	// Operate on a sub-view so that the stride differs from the
+	// number of columns and elements outside the view must survive.
+	base := NewDense(5, 6, nil)
+	for i := range base.mat.Data {
+		base.mat.Data[i] = float64(i)
+	}
+	orig := DenseCopyOf(base)
+	view := base.Slice(1, 4, 2, 5).(*Dense)
+
+	var rows []int
+	view.ApplyRowwise(func(i int, row []float64) {
+		rows = append(rows, i)
+		if len(row) != 3 {
+			t.Errorf("unexpected row length: got:%d want:3", len(row))
+		}
+		for k := range row {
+			if row[k] != orig.At(i+1, k+2) {
+				t.Errorf("unexpected row element %d of row %d: got:%v want:%v", k, i, row[k], orig.At(i+1, k+2))
+			}
+			row[k] *= 2
+		}
+	})
+	if !reflect.DeepEqual(rows, []int{0, 1, 2}) {
+		t.Errorf("unexpected row order: %v", rows)
+	}
+
+	var cols []int
+	view.ApplyColumnwise(func(j int, col []float64) {
+		cols = append(cols, j)
+		if len(col) != 3 {
+			t.Errorf("unexpected column length: got:%d want:3", len(col))
+		}
+		for k := range col {
+			if col[k] != 2*orig.At(k+1, j+2) {
+				t.Errorf("unexpected column element %d of column %d: got:%v want:%v", k, j, col[k], 2*orig.At(k+1, j+2))
+			}
+			col[k] += float64(j)
+		}
+	})
+	if !reflect.DeepEqual(cols, []int{0, 1, 2}) {
+		t.Errorf("unexpected column order: %v", cols)
+	}
+
+	r, c := base.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			want := orig.At(i, j)
+			if 1 <= i && i < 4 && 2 <= j && j < 5 {
+				want = 2*want + float64(j-2)
+			}
+			if got := base.At(i, j); got != want {
+				t.Errorf("unexpected element at (%d,%d): got:%v want:%v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDenseNormalizeColumnsRows(t *testing.T) {
+	t.Parallel()
+	a := NewDense(4, 5, []float64{
+		3, 0, -1, 2, 0,
+		4, 0, 2, -2, 0,
+		0, 0, -2, 1, 1,
+		0, 0, 0, 0, 0,
+	})
+	for _, norm := range []float64{1, 2, math.Inf(1)} {
+		// Normalize a sub-view to exercise non-unit strides.
+		base := DenseCopyOf(a)
+		view := base.Slice(0, 3, 0, 4).(*Dense)
+		view.NormalizeColumns(norm)
+		for j := 0; j < 4; j++ {
+			col := view.ColView(j)
+			want := 1.0
+			if j == 1 {
+				want = 0
+			}
+			if got := Norm(col, norm); math.Abs(got-want) > 1e-14 {
+				t.Errorf("unexpected norm %v of column %d: got:%v want:%v", norm, j, got, want)
+			}
+		}
+		if base.At(2, 4) != 1 || base.At(3, 0) != 0 {
+			t.Errorf("elements outside the view modified for norm %v", norm)
+		}
+
+		rows := DenseCopyOf(a)
+		rows.NormalizeRows(norm)
+		for i := 0; i < 4; i++ {
+			want := 1.0
+			if i == 3 {
+				want = 0
+			}
+			if got := Norm(rows.RowView(i), norm); math.Abs(got-want) > 1e-14 {
+				t.Errorf("unexpected norm %v of row %d: got:%v want:%v", norm, i, got, want)
+			}
+		}
+	}
+	if p, _ := panics(func() { a.NormalizeColumns(3) }); !p {
+		t.Error("expected panic for invalid norm")
+	}
+}
+
+func TestDenseCenterColumnsRows(t *testing.T) {
+	t.Parallel()
+	a := NewDense(3, 4, []float64{
+		1, 2, 0, -1,
+		3, 2, 6, -1,
+		5, 2, 3, 2,
+	})
+	var cols Dense
+	means := cols.CenterColumns(a.T())
+	if want := []float64{0.5, 2.5, 3}; !floats.EqualApprox(means, want, 1e-14) {
+		t.Errorf("unexpected row means of transpose: got:%v want:%v", means, want)
+	}
+	for j := 0; j < 3; j++ {
+		if sum := floats.Sum(Col(nil, j, &cols)); math.Abs(sum) > 1e-14 {
+			t.Errorf("column %d not centered: sum %v", j, sum)
+		}
+	}
+
+	c := DenseCopyOf(a)
+	means = c.CenterColumns(c)
+	if want := []float64{3, 2, 3, 0}; !floats.EqualApprox(means, want, 1e-14) {
+		t.Errorf("unexpected column means: got:%v want:%v", means, want)
+	}
+	want := NewDense(3, 4, []float64{
+		-2, 0, -3, -1,
+		0, 0, 3, -1,
+		2, 0, 0, 2,
+	})
+	if !EqualApprox(c, want, 1e-14) {
+		t.Errorf("unexpected centered columns:\n%v", Formatted(c))
+	}
+
+	var rows Dense
+	means = rows.CenterRows(a)
+	if want := []float64{0.5, 2.5, 3}; !floats.EqualApprox(means, want, 1e-14) {
+		t.Errorf("unexpected row means: got:%v want:%v", means, want)
+	}
+	var back Dense
+	back.CenterColumns(rows.T())
+	if !EqualApprox(back.T(), &rows, 1e-14) {
+		t.Error("centered rows are not centered")
+	}
+}
+
 func TestDenseClone(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -1685,6 +1984,175 @@ func TestDenseAugment(t *testing.T) {
 	testTwoInput(t, "Augment", &Dense{}, method, denseComparison, legalTypesAll, legalSizeSameHeight, 0)
 }
 
+func TestDenseTile(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		a       [][]float64
+		rowReps int
+		colReps int
+		e       [][]float64
+	}{
+		{
+			[][]float64{{1, 2}},
+			1, 1,
+			[][]float64{{1, 2}},
+		},
+		{
+			[][]float64{{1, 2}, {3, 4}},
+			2, 1,
+			[][]float64{{1, 2}, {3, 4}, {1, 2}, {3, 4}},
+		},
+		{
+			[][]float64{{1, 2, 3}},
+			1, 3,
+			[][]float64{{1, 2, 3, 1, 2, 3, 1, 2, 3}},
+		},
+		{
+			[][]float64{{1, 2}, {3, 4}},
+			2, 3,
+			[][]float64{
+				{1, 2, 1, 2, 1, 2},
+				{3, 4, 3, 4, 3, 4},
+				{1, 2, 1, 2, 1, 2},
+				{3, 4, 3, 4, 3, 4},
+			},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+
+		var s Dense
+		s.Tile(a, test.rowReps, test.colReps)
+		if !Equal(&s, NewDense(flatten(test.e))) {
+			t.Errorf("unexpected result for Tile test %d: %v tiled %d×%d = %v", i, a, test.rowReps, test.colReps, s)
+		}
+
+		s.Reset()
+		s.Tile(a.T(), test.colReps, test.rowReps)
+		if !Equal(&s, NewDense(flatten(test.e)).T()) {
+			t.Errorf("unexpected result for transposed Tile test %d", i)
+		}
+	}
+
+	for _, reps := range [][2]int{{0, 1}, {1, 0}, {-1, 2}} {
+		var s Dense
+		panicked, _ := panics(func() { s.Tile(NewDense(1, 1, nil), reps[0], reps[1]) })
+		if !panicked {
+			t.Errorf("expected panic for repetition counts %v", reps)
+		}
+	}
+}
+
+func TestDenseBlockDiag(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		blocks [][][]float64
+		e      [][]float64
+	}{
+		{
+			[][][]float64{{{1, 2}, {3, 4}}},
+			[][]float64{{1, 2}, {3, 4}},
+		},
+		{
+			[][][]float64{{{1, 2}, {3, 4}}, {{5}}},
+			[][]float64{{1, 2, 0}, {3, 4, 0}, {0, 0, 5}},
+		},
+		{
+			[][][]float64{{{1, 2, 3}}, {{4}, {5}}, {{6, 7}}},
+			[][]float64{
+				{1, 2, 3, 0, 0, 0},
+				{0, 0, 0, 4, 0, 0},
+				{0, 0, 0, 5, 0, 0},
+				{0, 0, 0, 0, 6, 7},
+			},
+		},
+	} {
+		blocks := make([]Matrix, len(test.blocks))
+		for j, b := range test.blocks {
+			blocks[j] = NewDense(flatten(b))
+		}
+
+		var s Dense
+		s.BlockDiag(blocks...)
+		if !Equal(&s, NewDense(flatten(test.e))) {
+			t.Errorf("unexpected result for BlockDiag test %d: got %v", i, s)
+		}
+
+		// Reuse of a non-empty receiver must zero the off-block elements.
+		for k := range s.mat.Data {
+			s.mat.Data[k] = math.NaN()
+		}
+		s.BlockDiag(blocks...)
+		if !Equal(&s, NewDense(flatten(test.e))) {
+			t.Errorf("unexpected result for BlockDiag test %d with non-empty receiver: got %v", i, s)
+		}
+	}
+
+	var s Dense
+	panicked, _ := panics(func() { s.BlockDiag() })
+	if !panicked {
+		t.Error("expected panic for no blocks")
+	}
+}
+
+func TestDenseDropColumnsRows(t *testing.T) {
+	t.Parallel()
+	a := [][]float64{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	for i, test := range []struct {
+		cols, rows []int
+		e          [][]float64
+	}{
+		{
+			cols: nil, rows: nil,
+			e: a,
+		},
+		{
+			cols: []int{1}, rows: nil,
+			e: [][]float64{{1, 3, 4}, {5, 7, 8}, {9, 11, 12}},
+		},
+		{
+			cols: []int{3, 0, 3}, rows: []int{1},
+			e: [][]float64{{2, 3}, {10, 11}},
+		},
+		{
+			cols: nil, rows: []int{2, 0},
+			e: [][]float64{{5, 6, 7, 8}},
+		},
+	} {
+		m := NewDense(flatten(a))
+		m.DropColumns(test.cols)
+		m.DropRows(test.rows)
+		if !Equal(m, NewDense(flatten(test.e))) {
+			t.Errorf("unexpected result for test %d: got %v", i, m)
+		}
+	}
+
+	// Dropping from a view must only touch the view.
+	m := NewDense(flatten(a))
+	v := m.Slice(0, 2, 1, 4).(*Dense)
+	v.DropColumns([]int{0})
+	if !Equal(v, NewDense(2, 2, []float64{3, 4, 7, 8})) {
+		t.Errorf("unexpected result for view: got %v", v)
+	}
+	if !Equal(m.Slice(2, 3, 0, 4), NewDense(1, 4, []float64{9, 10, 11, 12})) {
+		t.Errorf("unexpected modification outside view: got %v", m)
+	}
+
+	for _, fn := range []func(){
+		func() { NewDense(flatten(a)).DropColumns([]int{4}) },
+		func() { NewDense(flatten(a)).DropRows([]int{-1}) },
+		func() { NewDense(flatten(a)).DropRows([]int{0, 1, 2}) },
+		func() { NewDense(flatten(a)).DropColumns([]int{0, 1, 2, 3}) },
+	} {
+		if panicked, _ := panics(fn); !panicked {
+			t.Error("expected panic for invalid drop")
+		}
+	}
+}
+
 func TestDenseRankOne(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -136,6 +136,8 @@ var (
 	ErrSliceLengthMismatch = Error{"mat: input slice length mismatch"}
 	ErrNotPSD              = Error{"mat: input not positive symmetric definite"}
 	ErrFailedEigen         = Error{"mat: eigendecomposition not successful"}
+	ErrFailedSVD           = Error{"mat: singular value decomposition not successful"}
+	ErrIterationLimit      = Error{"mat: iteration limit reached before convergence"}
 )
 
 // ErrorStack represents matrix handling errors that have been recovered by Maybe wrappers.
@@ -149,3 +151,18 @@ type ErrorStack struct {
 }
 
 func (err ErrorStack) Error() string { return err.Err.Error() }
+
+// ApproxError is the error returned when the relative error of a low-rank
+// approximation exceeds the requested tolerance.
+type ApproxError struct {
+	// RelError is the measured relative
+	// Frobenius norm error ‖A - Â‖_F / ‖A‖_F.
+	RelError float64
+
+	// Tol is the requested tolerance.
+	Tol float64
+}
+
+func (err *ApproxError) Error() string {
+	return fmt.Sprintf("mat: relative approximation error %.4e exceeds tolerance %.4e", err.RelError, err.Tol)
+}
@@ -0,0 +1,595 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// FrobeniusNormEstimate returns a randomized estimate of the Frobenius norm of
+// a using the given number of standard normal probe vectors z, for which
+//  E[‖a z‖²] = ‖a‖_F².
+// The a matrix is only accessed through a single product with an n×samples
+// probe matrix, so the estimate is considerably cheaper than Norm(a, 2) for
+// matrices whose elements are expensive to access.
+//
+// FrobeniusNormEstimate also returns the estimated standard error of the norm
+// estimate. The standard error is zero if samples is one.
+//
+// If rnd is nil, the global rand source is used. FrobeniusNormEstimate will
+// panic if samples is less than one or if a has zero size.
+func FrobeniusNormEstimate(a Matrix, samples int, rnd *rand.Rand) (norm, stdErr float64) {
+	if samples < 1 {
+		panic("mat: number of samples must be positive")
+	}
+	r, c := a.Dims()
+	if r == 0 || c == 0 {
+		panic(ErrShape)
+	}
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+
+	z := getWorkspace(c, samples, false)
+	defer putWorkspace(z)
+	for i := range z.mat.Data {
+		z.mat.Data[i] = normFloat64()
+	}
+	az := getWorkspace(r, samples, false)
+	defer putWorkspace(az)
+	az.Mul(a, z)
+
+	// Accumulate the mean and variance of the squared
+	// norms of the probe images with Welford's method.
+	var mean, m2 float64
+	for j := 0; j < samples; j++ {
+		var sq float64
+		for i := 0; i < r; i++ {
+			v := az.mat.Data[i*az.mat.Stride+j]
+			sq += v * v
+		}
+		d := sq - mean
+		mean += d / float64(j+1)
+		m2 += d * (sq - mean)
+	}
+	norm = math.Sqrt(mean)
+	if samples == 1 || norm == 0 {
+		return norm, 0
+	}
+	// The standard error of the squared norm is propagated
+	// to the norm by the delta method, d√x = dx/(2√x).
+	seSq := math.Sqrt(m2 / float64(samples-1) / float64(samples))
+	return norm, seSq / (2 * norm)
+}
+
+// TraceInvEstimate returns a randomized estimate of the trace of the inverse
+// of the symmetric positive definite matrix a, and the estimated standard
+// error of the estimate. The estimate is the Hutchinson estimator
+//  tr(a⁻¹) ≈ 1/probes Σ_i zᵢᵀ a⁻¹ zᵢ
+// with Rademacher probe vectors zᵢ, where each a⁻¹ zᵢ is computed by at most
+// cgIter iterations of the conjugate gradient method, stopping when the norm
+// of the residual is below tol times the norm of zᵢ. a is only accessed
+// through matrix-vector products, and no conjugate gradient solve is checked
+// for convergence, so cgIter and tol bound the cost and the bias of the
+// estimate. The standard error is zero if probes is one.
+//
+// If rnd is nil, the global rand source is used. TraceInvEstimate will panic
+// if probes or cgIter is less than one, if tol is negative, or if a has zero
+// size.
+func TraceInvEstimate(a Symmetric, probes, cgIter int, tol float64, rnd *rand.Rand) (trace, stdErr float64) {
+	if probes < 1 {
+		panic("mat: number of probes must be positive")
+	}
+	if cgIter < 1 {
+		panic("mat: number of iterations must be positive")
+	}
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	n := a.Symmetric()
+	if n == 0 {
+		panic(ErrShape)
+	}
+	uint64 := rand.Uint64
+	if rnd != nil {
+		uint64 = rnd.Uint64
+	}
+
+	z := NewVecDense(n, nil)
+	x := NewVecDense(n, nil)
+	var ws cgWorkspace
+	var mean, m2 float64
+	for k := 0; k < probes; k++ {
+		for i := 0; i < n; {
+			bits := uint64()
+			for b := 0; b < 64 && i < n; b, i = b+1, i+1 {
+				z.setVec(i, float64(int(bits>>b&1)*2-1))
+			}
+		}
+		ws.solve(x, a, z, cgIter, tol)
+		q := Dot(z, x)
+		d := q - mean
+		mean += d / float64(k+1)
+		m2 += d * (q - mean)
+	}
+	if probes == 1 {
+		return mean, 0
+	}
+	return mean, math.Sqrt(m2 / float64(probes-1) / float64(probes))
+}
+
+// TraceEstimate returns the Hutchinson estimate of the trace of the n×n
+// operator op,
+//  tr(op) ≈ 1/probes Σ_i zᵢᵀ op zᵢ,
+// and the estimated standard error of the estimate, using probe vectors zᵢ
+// drawn as specified by kind, which must be SketchRademacher or
+// SketchGaussian. For symmetric op, Rademacher probes give the smaller
+// variance per probe, 2‖op‖_F² less twice the sum of the squared diagonal
+// elements. op is only accessed through probes matrix-vector products, so the
+// trace of a function f(A) of a matrix, such as A⁻¹ or log A, can be
+// estimated by an operator that applies f(A) to vectors
+// without forming it. The standard error is zero if probes is one.
+//
+// If rnd is nil, the global rand source is used. TraceEstimate will panic if
+// probes is less than one, if op is not square or if kind is not
+// SketchRademacher or SketchGaussian.
+func TraceEstimate(op LinearOp, probes int, kind SketchKind, rnd *rand.Rand) (trace, stdErr float64) {
+	if probes < 1 {
+		panic("mat: number of probes must be positive")
+	}
+	r, c := op.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	z := makeRandomMatrix(r, probes, probeDist(kind), rnd)
+	var az Dense
+	mulOp(&az, op, false, z)
+
+	var mean, m2 float64
+	for k := 0; k < probes; k++ {
+		var q float64
+		for i := 0; i < r; i++ {
+			q += z.at(i, k) * az.at(i, k)
+		}
+		d := q - mean
+		mean += d / float64(k+1)
+		m2 += d * (q - mean)
+	}
+	if probes == 1 {
+		return mean, 0
+	}
+	return mean, math.Sqrt(m2 / float64(probes-1) / float64(probes))
+}
+
+// TraceEstimateHutchPP returns the Hutch++ estimate of the trace of the n×n
+// operator op using probes matrix-vector products, following Meyer, Musco,
+// Musco and Woodruff, "Hutch++: Optimal stochastic trace estimation", SOSA
+// 2021. A third of the products sketch the range of op with Rademacher
+// vectors S, whose orthonormal basis Q = orth(op S) captures the dominant
+// eigenvalues exactly through tr(Qᵀ op Q), and the remaining third estimate
+// the trace of the deflated operator (I - Q Qᵀ) op (I - Q Qᵀ) with Hutchinson's
+// estimator. For operators with decaying spectra, such as positive
+// semidefinite matrices of low effective rank, the error decreases as
+// 1/probes rather than 1/√probes for TraceEstimate.
+//
+// If rnd is nil, the global rand source is used. TraceEstimateHutchPP will
+// panic if probes is less than three or if op is not square.
+func TraceEstimateHutchPP(op LinearOp, probes int, rnd *rand.Rand) float64 {
+	if probes < 3 {
+		panic("mat: Hutch++ requires at least three probes")
+	}
+	n, c := op.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	k := min(probes/3, n)
+	l := (probes - k) / 2
+	if l == 0 {
+		l = 1
+	}
+
+	// [Q] = orth(op × S) = n × k
+	var as Dense
+	mulOp(&as, op, false, makeRandomMatrix(n, k, distRademacher, rnd))
+	q := orthonormalBasis(&as)
+
+	// tr(Qᵀ × op × Q)
+	var aq Dense
+	mulOp(&aq, op, false, q)
+	var trace float64
+	for j := 0; j < k; j++ {
+		for i := 0; i < n; i++ {
+			trace += q.at(i, j) * aq.at(i, j)
+		}
+	}
+
+	// [G] = [(I - Q Qᵀ) × G] = n × l
+	g := makeRandomMatrix(n, l, distRademacher, rnd)
+	var qtg, qqtg Dense
+	qtg.Mul(q.T(), g)
+	qqtg.Mul(q, &qtg)
+	g.Sub(g, &qqtg)
+	var ag Dense
+	mulOp(&ag, op, false, g)
+	var tail float64
+	for j := 0; j < l; j++ {
+		for i := 0; i < n; i++ {
+			tail += g.at(i, j) * ag.at(i, j)
+		}
+	}
+	return trace + tail/float64(l)
+}
+
+// probeDist returns the random distribution of probe vectors of kind.
+func probeDist(kind SketchKind) randomDist {
+	switch kind {
+	case SketchRademacher:
+		return distRademacher
+	case SketchGaussian:
+		return distNormal
+	default:
+		panic("mat: probe kind must be SketchRademacher or SketchGaussian")
+	}
+}
+
+// LogAbsDetEstimate returns a randomized estimate of the logarithm of the
+// absolute value of the determinant of the square matrix A,
+//  log|det A| = Σ_i log σ_i,
+// from a rank-rank randomized singular value decomposition of A. The leading
+// rank singular values are taken from the decomposition, and the n-rank
+// trailing singular values are replaced by their root mean square, which is
+// known exactly as the energy of A not captured by the leading values,
+// ‖A‖_F² - Σ_{i≤rank} σ_i². The estimate is exact when rank is n, up to the
+// accuracy of the randomized singular values, and when the trailing singular
+// values are equal, and otherwise it overestimates log|det A| by the
+// concavity of the logarithm. The estimate is approximate because it relies
+// on randomized singular values, and it is only accurate when the trailing
+// singular values of A are clustered, for example for a low-rank matrix plus
+// a multiple of the identity. The sign of the determinant is not determined
+// by the singular values.
+//
+// The decomposition is computed with the parameters chosen by RSVDAuto.
+// LogAbsDetEstimate returns ok false if A is numerically singular, when a
+// leading singular value is less than n·ε·σ_max or the root mean square of the
+// trailing singular values is less than √(n·ε)·σ_max, below which it cannot be
+// resolved from the captured energy, or if the decomposition fails.
+//
+// If rnd is nil, the global rand source is used. LogAbsDetEstimate will panic
+// if A is not square or if rank is not in [1, n].
+func LogAbsDetEstimate(A Matrix, rank int, rnd *rand.Rand) (logAbsDet float64, ok bool) {
+	r, c := A.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	n := r
+	if rank < 1 || n < rank {
+		panic(ErrShape)
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(A, rank, withRand(rnd), RSVDAuto()) {
+		return 0, false
+	}
+	s := rsvd.Values(nil)
+	eps := float64(n) * (1.0 / (1 << 53))
+	tol := eps * s[0]
+	var captured float64
+	for _, v := range s {
+		if v <= tol {
+			return 0, false
+		}
+		logAbsDet += math.Log(v)
+		captured += v * v
+	}
+	if tail := n - len(s); tail > 0 {
+		norm := Norm(A, 2)
+		rem := (norm*norm - captured) / float64(tail)
+		if rem <= eps*s[0]*s[0] {
+			return 0, false
+		}
+		logAbsDet += 0.5 * float64(tail) * math.Log(rem)
+	}
+	return logAbsDet, true
+}
+
+// stableRankProbes is the minimum number of probe vectors
+// used by StableRank to estimate the Frobenius norm.
+const stableRankProbes = 100
+
+// StableRank returns a randomized estimate of the stable rank of a,
+//  ‖a‖_F² / ‖a‖_2²,
+// which is at most the rank of a, and unlike the rank is insensitive to small
+// singular values, so it is a continuous measure of the number of significant
+// directions of noisy data.
+//
+// The Frobenius norm is estimated by FrobeniusNormEstimate with max(rank, 100)
+// probe vectors, and the spectral norm is the largest singular value of a rank-rank
+// randomized singular value decomposition of a using the parameters chosen by
+// RSVDAuto. Both estimates are randomized. The spectral norm estimate never
+// exceeds ‖a‖_2, so the stable rank tends to be overestimated, and larger
+// values of rank reduce the variance of the estimate. The relative standard
+// error of the Frobenius norm estimate is at most 1/√(2·probes), about 7% for
+// 100 probes, and smaller when many singular values are significant. The returned value is
+// clamped to [1, min(m,n)], and StableRank returns zero if a is zero.
+//
+// If rnd is nil, the global rand source is used. StableRank will panic if
+// rank is not in [1, min(m,n)], or with ErrFailedSVD if the decomposition
+// fails.
+func StableRank(a Matrix, rank int, rnd *rand.Rand) float64 {
+	m, n := a.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic(ErrShape)
+	}
+	frob, _ := FrobeniusNormEstimate(a, max(rank, stableRankProbes), rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank, withRand(rnd), RSVDAuto()) {
+		panic(ErrFailedSVD)
+	}
+	spec := rsvd.Values(nil)[0]
+	if spec == 0 {
+		return 0
+	}
+	r := (frob / spec) * (frob / spec)
+	return math.Max(1, math.Min(r, float64(min(m, n))))
+}
+
+// norm2EstMaxIter is the maximum number of power iterations used by Norm2Est.
+const norm2EstMaxIter = 1000
+
+// Norm2Est returns a randomized estimate of the spectral norm ‖A‖_2, the
+// largest singular value of A, without computing a singular value
+// decomposition. The estimate is computed by the power method on AᵀA started
+// from a Gaussian vector x drawn from rnd, with the estimate at each step
+//  σ = ‖Aᵀ A x‖ / ‖A x‖, x ← Aᵀ A x / ‖Aᵀ A x‖,
+// which is a non-decreasing lower bound of ‖A‖_2. The iteration stops when the
+// relative change of σ between successive steps is at most tol, or after 1000
+// steps. A is only accessed through two matrix-vector products per step. The
+// estimate converges at the rate (σ₂/σ₁)², and since the change between steps
+// underestimates the remaining error when σ₂ is close to σ₁, the relative
+// error of the estimate may exceed tol in that case.
+//
+// If A is zero, Norm2Est returns zero. If rnd is nil, the global rand source
+// is used. Norm2Est will panic if tol is negative or if A has zero size.
+func Norm2Est(A Matrix, tol float64, rnd *rand.Rand) float64 {
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	m, n := A.Dims()
+	if m == 0 || n == 0 {
+		panic(ErrShape)
+	}
+
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+	x := NewVecDense(n, nil)
+	for i := range x.mat.Data {
+		x.mat.Data[i] = normFloat64()
+	}
+	x.ScaleVec(1/Norm(x, 2), x)
+	y := NewVecDense(m, nil)
+	var sigma float64
+	for it := 0; it < norm2EstMaxIter; it++ {
+		y.MulVec(A, x)
+		ny := Norm(y, 2)
+		if ny == 0 {
+			// x is in the null space of A, which for a
+			// random start means that A is zero.
+			return 0
+		}
+		x.MulVec(A.T(), y)
+		nx := Norm(x, 2)
+		prev := sigma
+		sigma = nx / ny
+		x.ScaleVec(1/nx, x)
+		if it > 0 && math.Abs(sigma-prev) <= tol*sigma {
+			break
+		}
+	}
+	return sigma
+}
+
+// smallestEigenOversample and smallestEigenPowerIter are the number of
+// additional sketch columns and of power iterations used by SmallestEigen.
+const (
+	smallestEigenOversample = 10
+	smallestEigenPowerIter  = 2
+)
+
+// SmallestEigen returns the k smallest eigenvalues of the symmetric positive
+// definite matrix a in ascending order, and an n×k matrix with the
+// corresponding orthonormal eigenvectors in its columns.
+//
+// The eigenpairs are computed by shift-invert: a randomized range finder is
+// applied to a⁻¹, whose largest eigenvalues are the reciprocals of the
+// smallest eigenvalues of a, with a sketch of k+10 Gaussian columns and two
+// power iterations, followed by a Rayleigh–Ritz projection of a onto the
+// range basis. a is only accessed through matrix-vector products. Each
+// product with a⁻¹ is computed by at most cgIter iterations of the conjugate
+// gradient method, stopping when the residual norm is below tol times the
+// norm of the right-hand side, so 3(k+10) linear systems are solved in
+// total. The accuracy of the eigenpairs depends on the accuracy of these
+// inner solves and on the gap between the k'th and the following eigenvalues.
+//
+// SmallestEigen requires a to be positive definite. It returns ErrNotPSD if
+// the conjugate gradient method detects that a is not positive definite,
+// although an indefinite a is not guaranteed to be detected, and
+// ErrFailedEigen if the projected eigendecomposition fails.
+//
+// If rnd is nil, the global rand source is used. SmallestEigen will panic if
+// k is not in [1, n], if cgIter is less than one or if tol is negative.
+func SmallestEigen(a Symmetric, k, cgIter int, tol float64, rnd *rand.Rand) ([]float64, *Dense, error) {
+	n := a.Symmetric()
+	if k < 1 || n < k {
+		panic(ErrShape)
+	}
+	if cgIter < 1 {
+		panic("mat: number of iterations must be positive")
+	}
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	l := min(k+smallestEigenOversample, n)
+
+	// Apply a⁻¹ to the columns of z in place.
+	var ws cgWorkspace
+	b := NewVecDense(n, nil)
+	x := NewVecDense(n, nil)
+	invMul := func(z *Dense) bool {
+		for j := 0; j < l; j++ {
+			col := z.ColView(j).(*VecDense)
+			b.CopyVec(col)
+			if _, ok := ws.solve(x, a, b, cgIter, tol); !ok {
+				return false
+			}
+			col.CopyVec(x)
+		}
+		return true
+	}
+
+	z := makeRandomMatrix(n, l, distNormal, rnd)
+	if !invMul(z) {
+		return nil, nil, ErrNotPSD
+	}
+	q := orthonormalBasis(z)
+	for it := 0; it < smallestEigenPowerIter; it++ {
+		z.Copy(q)
+		if !invMul(z) {
+			return nil, nil, ErrNotPSD
+		}
+		q = orthonormalBasis(z)
+	}
+
+	// Rayleigh–Ritz projection of a onto the range basis.
+	var aq, t Dense
+	aq.Mul(a, q)
+	t.Mul(q.T(), &aq)
+	ts := NewSymDense(l, nil)
+	for i := 0; i < l; i++ {
+		for j := i; j < l; j++ {
+			ts.SetSym(i, j, 0.5*(t.at(i, j)+t.at(j, i)))
+		}
+	}
+	var eig EigenSym
+	if !eig.Factorize(ts, true) {
+		return nil, nil, ErrFailedEigen
+	}
+	// EigenSym returns the eigenvalues in ascending order.
+	values := eig.Values(nil)[:k:k]
+	var w Dense
+	eig.VectorsTo(&w)
+	vecs := NewDense(n, k, nil)
+	vecs.Mul(q, w.slice(0, l, 0, k))
+	return values, vecs, nil
+}
+
+// psdProbeIter is the number of power iterations used by IsPSD.
+const psdProbeIter = 64
+
+// IsPSD returns whether the symmetric matrix a is positive semidefinite to
+// within tol, that is whether its smallest eigenvalue is not less than -tol.
+//
+// IsPSD is a cheap randomized check. The diagonal of a is inspected, and the
+// smallest eigenvalue is then probed with 64 power iterations on the
+// shifted matrix σI - a, where σ = ‖a‖_∞ bounds the spectral radius of a,
+// starting from a random Gaussian vector. a is only accessed through O(n)
+// matrix-vector products. Every Rayleigh quotient computed is an upper bound
+// of the smallest eigenvalue, so IsPSD never reports a positive semidefinite
+// matrix as indefinite. An indefinite matrix may be reported as positive
+// semidefinite when the start vector is nearly orthogonal to the eigenvectors
+// of the negative eigenvalues. By the analysis of the power method with a
+// random start by Kuczyński and Woźniakowski, the probability that the
+// estimate of the largest eigenvalue σ-λ_min of the shifted matrix has a
+// relative error of at least ε after p iterations is at most
+//  0.824·√n·(1-ε)^(p-1/2),
+// so negative eigenvalues that are small relative to σ are the most likely to
+// be missed, particularly for large n.
+//
+// The start vector is drawn from rnd. If rnd is nil, the global rand source is
+// used. IsPSD will panic if tol is negative.
+func IsPSD(a Symmetric, tol float64, rnd *rand.Rand) bool {
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	n := a.Symmetric()
+	if n == 0 {
+		return true
+	}
+	for i := 0; i < n; i++ {
+		if a.At(i, i) < -tol {
+			return false
+		}
+	}
+	sigma := Norm(a, math.Inf(1))
+	if sigma == 0 {
+		return true
+	}
+
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+	x := NewVecDense(n, nil)
+	for i := range x.mat.Data {
+		x.mat.Data[i] = normFloat64()
+	}
+	x.ScaleVec(1/Norm(x, 2), x)
+	var y VecDense
+	for it := 0; it < psdProbeIter; it++ {
+		y.MulVec(a, x)
+		if Dot(x, &y) < -tol {
+			return false
+		}
+		// Apply the shifted matrix σI - a to the iterate.
+		x.AddScaledVec(&y, -sigma, x)
+		norm := Norm(x, 2)
+		if norm == 0 {
+			// The iterate is an eigenvector of a with eigenvalue σ,
+			// the largest possible, so it gives no further information.
+			return true
+		}
+		x.ScaleVec(-1/norm, x)
+	}
+	return true
+}
+
+// cgWorkspace holds the vectors used by the conjugate gradient method.
+type cgWorkspace struct {
+	r, p, ap VecDense
+}
+
+// solve computes an approximate solution x of a x = b for a symmetric
+// positive definite a with at most maxIter iterations of the conjugate
+// gradient method starting from zero, stopping when the residual norm is at
+// most tol times the norm of b. It returns the number of iterations performed,
+// and false if a non-positive curvature pᵀ a p showed that a is not positive
+// definite.
+func (ws *cgWorkspace) solve(x *VecDense, a Symmetric, b *VecDense, maxIter int, tol float64) (int, bool) {
+	x.Zero()
+	ws.r.CloneFromVec(b)
+	ws.p.CloneFromVec(b)
+	rr := Dot(&ws.r, &ws.r)
+	stop := tol * tol * rr
+	for k := 0; k < maxIter; k++ {
+		if rr <= stop || rr == 0 {
+			return k, true
+		}
+		ws.ap.MulVec(a, &ws.p)
+		pap := Dot(&ws.p, &ws.ap)
+		if pap <= 0 {
+			return k, false
+		}
+		alpha := rr / pap
+		x.AddScaledVec(x, alpha, &ws.p)
+		ws.r.AddScaledVec(&ws.r, -alpha, &ws.ap)
+		rrNew := Dot(&ws.r, &ws.r)
+		ws.p.AddScaledVec(&ws.r, rrNew/rr, &ws.p)
+		rr = rrNew
+	}
+	return maxIter, true
+}
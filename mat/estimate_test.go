@@ -0,0 +1,433 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestFrobeniusNormEstimate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c, samples int
+	}{
+		{r: 10, c: 10, samples: 500},
+		{r: 50, c: 20, samples: 2000},
+		{r: 5, c: 40, samples: 1000},
+	} {
+		a := NewDense(test.r, test.c, nil)
+		for i := range a.mat.Data {
+			a.mat.Data[i] = rnd.NormFloat64()
+		}
+		want := Norm(a, 2)
+		got, se := FrobeniusNormEstimate(a, test.samples, rnd)
+		if se <= 0 {
+			t.Errorf("unexpected non-positive standard error for %d×%d: %v", test.r, test.c, se)
+		}
+		if math.Abs(got-want) > 5*se {
+			t.Errorf("unexpected estimate for %d×%d: got:%v want:%v±%v", test.r, test.c, got, want, se)
+		}
+	}
+
+	got, se := FrobeniusNormEstimate(NewDense(3, 4, nil), 10, rnd)
+	if got != 0 || se != 0 {
+		t.Errorf("unexpected estimate for zero matrix: got:%v±%v", got, se)
+	}
+}
+
+func TestTraceInvEstimate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 30
+
+	// A = Q diag(d) Qᵀ has tr(A⁻¹) = Σ 1/dᵢ.
+	var qr QR
+	qr.Factorize(NewRandomNormalDense(n, n, rnd))
+	var q Dense
+	qr.QTo(&q)
+	d := NewDiagDense(n, nil)
+	var want float64
+	for i := 0; i < n; i++ {
+		v := 1 + float64(i)/2
+		d.SetDiag(i, v)
+		want += 1 / v
+	}
+	var a Dense
+	a.Product(&q, d, q.T())
+	sym := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			sym.SetSym(i, j, 0.5*(a.At(i, j)+a.At(j, i)))
+		}
+	}
+
+	got, se := TraceInvEstimate(sym, 2000, n, 1e-12, rnd)
+	if se <= 0 {
+		t.Errorf("unexpected non-positive standard error: %v", se)
+	}
+	if math.Abs(got-want) > 4*se {
+		t.Errorf("estimate too far from trace of inverse: got:%v want:%v se:%v", got, want, se)
+	}
+
+	// A diagonal matrix is estimated exactly by Rademacher probes.
+	got, se = TraceInvEstimate(d, 3, n, 1e-14, rnd)
+	if math.Abs(got-want) > 1e-12 || se > 1e-12 {
+		t.Errorf("unexpected estimate for diagonal matrix: got:%v want:%v se:%v", got, want, se)
+	}
+
+	if p, _ := panics(func() { TraceInvEstimate(sym, 0, n, 0, rnd) }); !p {
+		t.Error("expected panic for zero probes")
+	}
+	if p, _ := panics(func() { TraceInvEstimate(sym, 1, 0, 0, rnd) }); !p {
+		t.Error("expected panic for zero iterations")
+	}
+}
+
+func TestTraceEstimate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 40
+
+	a := NewRandomNormalDense(n, n, rnd)
+	want := Trace(a)
+	for _, kind := range []SketchKind{SketchRademacher, SketchGaussian} {
+		got, se := TraceEstimate(NewMatrixOp(a), 4000, kind, rnd)
+		if se <= 0 {
+			t.Errorf("unexpected non-positive standard error for kind %d: %v", kind, se)
+		}
+		if math.Abs(got-want) > 4*se {
+			t.Errorf("estimate too far from trace for kind %d: got:%v want:%v se:%v", kind, got, want, se)
+		}
+	}
+
+	// A diagonal matrix is estimated exactly by Rademacher probes.
+	d := NewDiagDense(n, nil)
+	want = 0
+	for i := 0; i < n; i++ {
+		d.SetDiag(i, float64(i+1))
+		want += float64(i + 1)
+	}
+	got, se := TraceEstimate(NewMatrixOp(d), 3, SketchRademacher, rnd)
+	if math.Abs(got-want) > 1e-12 || se > 1e-12 {
+		t.Errorf("unexpected estimate for diagonal matrix: got:%v want:%v se:%v", got, want, se)
+	}
+
+	if p, _ := panics(func() { TraceEstimate(NewMatrixOp(a), 0, SketchRademacher, rnd) }); !p {
+		t.Error("expected panic for zero probes")
+	}
+	if p, _ := panics(func() { TraceEstimate(NewMatrixOp(NewDense(3, 4, nil)), 1, SketchRademacher, rnd) }); !p {
+		t.Error("expected panic for non-square operator")
+	}
+	if p, _ := panics(func() { TraceEstimate(NewMatrixOp(a), 1, SketchSRHT, rnd) }); !p {
+		t.Error("expected panic for unsupported probe kind")
+	}
+}
+
+func TestTraceEstimateHutchPP(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n      = 100
+		probes = 60
+		trials = 20
+	)
+
+	// A = Q diag(d) Qᵀ with rapidly decaying eigenvalues is captured
+	// almost exactly by the low-rank part of Hutch++.
+	var qr QR
+	qr.Factorize(NewRandomNormalDense(n, n, rnd))
+	var q Dense
+	qr.QTo(&q)
+	d := NewDiagDense(n, nil)
+	var want float64
+	for i := 0; i < n; i++ {
+		v := math.Pow(0.7, float64(i))
+		d.SetDiag(i, v)
+		want += v
+	}
+	var a Dense
+	a.Product(&q, d, q.T())
+
+	var errHutch, errHutchPP float64
+	for i := 0; i < trials; i++ {
+		got, _ := TraceEstimate(NewMatrixOp(&a), probes, SketchRademacher, rnd)
+		errHutch += math.Abs(got - want)
+		errHutchPP += math.Abs(TraceEstimateHutchPP(NewMatrixOp(&a), probes, rnd) - want)
+	}
+	if errHutchPP >= errHutch {
+		t.Errorf("Hutch++ not more accurate than Hutchinson: got:%v Hutchinson:%v", errHutchPP/trials, errHutch/trials)
+	}
+	if errHutchPP/trials > 1e-2*want {
+		t.Errorf("Hutch++ error too large: got:%v trace:%v", errHutchPP/trials, want)
+	}
+
+	// The trace of a matrix of rank at most probes/3 is exact.
+	var l Dense
+	l.Mul(NewRandomNormalDense(n, 5, rnd), NewRandomNormalDense(5, n, rnd))
+	got := TraceEstimateHutchPP(NewMatrixOp(&l), 15, rnd)
+	if want := Trace(&l); math.Abs(got-want) > 1e-10*math.Abs(want)+1e-10 {
+		t.Errorf("unexpected estimate for low rank matrix: got:%v want:%v", got, want)
+	}
+
+	if p, _ := panics(func() { TraceEstimateHutchPP(NewMatrixOp(&a), 2, rnd) }); !p {
+		t.Error("expected panic for too few probes")
+	}
+	if p, _ := panics(func() { TraceEstimateHutchPP(NewMatrixOp(NewDense(3, 4, nil)), 3, rnd) }); !p {
+		t.Error("expected panic for non-square operator")
+	}
+}
+
+func TestNorm2Est(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{m: 1, n: 1},
+		{m: 1, n: 10},
+		{m: 10, n: 1},
+		{m: 50, n: 30},
+		{m: 30, n: 50},
+	} {
+		a := NewRandomNormalDense(test.m, test.n, rnd)
+		var svd SVD
+		if !svd.Factorize(a, SVDNone) {
+			t.Fatalf("SVD failed for %d×%d", test.m, test.n)
+		}
+		want := svd.Values(nil)[0]
+		got := Norm2Est(a, 1e-12, rnd)
+		if got > want*(1+1e-12) {
+			t.Errorf("unexpected estimate above spectral norm for %d×%d: got:%v want:%v", test.m, test.n, got, want)
+		}
+		// Random matrices may have close leading singular values,
+		// so the accuracy is less than the tolerance.
+		if math.Abs(got-want) > 1e-4*want {
+			t.Errorf("unexpected spectral norm estimate for %d×%d: got:%v want:%v", test.m, test.n, got, want)
+		}
+	}
+
+	// A matrix with a spectral gap converges quickly.
+	const n = 40
+	sv := make([]float64, n)
+	for i := range sv {
+		sv[i] = math.Pow(0.5, float64(i))
+	}
+	a := NewTestMatrix(n, n, sv, rnd)
+	want := sv[0]
+	if got := Norm2Est(a, 1e-10, rnd); math.Abs(got-want) > 1e-8*want {
+		t.Errorf("unexpected spectral norm estimate for matrix with spectral gap: got:%v want:%v", got, want)
+	}
+	if got := Norm2Est(a, 0.1, nil); got > want*(1+1e-12) || got < 0.5*want {
+		t.Errorf("unexpected spectral norm estimate with loose tolerance: got:%v want:%v", got, want)
+	}
+	got1 := Norm2Est(a, 0.1, rand.New(rand.NewSource(2)))
+	got2 := Norm2Est(a, 0.1, rand.New(rand.NewSource(2)))
+	if got1 != got2 {
+		t.Errorf("unexpected spectral norm estimates from the same source: %v != %v", got1, got2)
+	}
+
+	if got := Norm2Est(NewDense(4, 3, nil), 1e-8, rnd); got != 0 {
+		t.Errorf("unexpected spectral norm estimate for zero matrix: got:%v want:0", got)
+	}
+
+	if p, _ := panics(func() { Norm2Est(a, -1, rnd) }); !p {
+		t.Error("expected panic for negative tolerance")
+	}
+	if p, _ := panics(func() { Norm2Est(&Dense{}, 0, rnd) }); !p {
+		t.Error("expected panic for empty matrix")
+	}
+}
+
+func TestSmallestEigen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, k = 50, 3
+
+	var qr QR
+	qr.Factorize(NewRandomNormalDense(n, n, rnd))
+	var q Dense
+	qr.QTo(&q)
+	d := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		d.SetDiag(i, 0.1*float64(i+1)*float64(i+1))
+	}
+	var a Dense
+	a.Product(&q, d, q.T())
+	sym := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			sym.SetSym(i, j, 0.5*(a.At(i, j)+a.At(j, i)))
+		}
+	}
+
+	values, vecs, err := SmallestEigen(sym, k, 10*n, 1e-14, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, v := range values {
+		want := d.At(i, i)
+		if math.Abs(v-want) > 1e-6*want {
+			t.Errorf("unexpected eigenvalue %d: got:%v want:%v", i, v, want)
+		}
+		// Check the eigenvector residual ‖A v - λ v‖.
+		var res VecDense
+		res.MulVec(sym, vecs.ColView(i))
+		res.AddScaledVec(&res, -v, vecs.ColView(i))
+		if r := Norm(&res, 2); r > 1e-2 {
+			t.Errorf("unexpected eigenvector residual %d: %v", i, r)
+		}
+	}
+	if !hasOrthonormalColumns(vecs, 1e-12) {
+		t.Error("eigenvectors not orthonormal")
+	}
+
+	// An indefinite matrix is detected by the inner solves.
+	indef := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		indef.SetSym(i, i, float64(i-n/2)+0.5)
+	}
+	if _, _, err := SmallestEigen(indef, k, 10*n, 1e-14, rnd); err != ErrNotPSD {
+		t.Errorf("unexpected error for indefinite matrix: got:%v want:%v", err, ErrNotPSD)
+	}
+
+	if p, _ := panics(func() { SmallestEigen(sym, n+1, n, 0, rnd) }); !p {
+		t.Error("expected panic for too many eigenvalues")
+	}
+}
+
+func TestIsPSD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 30
+
+	var qr QR
+	qr.Factorize(NewRandomNormalDense(n, n, rnd))
+	var q Dense
+	qr.QTo(&q)
+	rotate := func(eigs []float64) *SymDense {
+		var a Dense
+		a.Product(&q, NewDiagDense(n, eigs), q.T())
+		sym := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				sym.SetSym(i, j, 0.5*(a.At(i, j)+a.At(j, i)))
+			}
+		}
+		return sym
+	}
+	spectrum := func(smallest float64) []float64 {
+		eigs := make([]float64, n)
+		eigs[0] = smallest
+		for i := 1; i < n; i++ {
+			eigs[i] = float64(i)
+		}
+		return eigs
+	}
+
+	for _, test := range []struct {
+		a    Symmetric
+		tol  float64
+		want bool
+	}{
+		{a: rotate(spectrum(0.5)), want: true},
+		{a: rotate(spectrum(0)), tol: 1e-10, want: true},
+		{a: rotate(spectrum(-5)), want: false},
+		{a: rotate(spectrum(-5)), tol: 10, want: true},
+		{a: NewDiagDense(3, []float64{1, -1e-3, 2}), want: false},
+		{a: NewSymDense(3, nil), want: true},
+	} {
+		if got := IsPSD(test.a, test.tol, rnd); got != test.want {
+			t.Errorf("unexpected result for tol=%v: got:%t want:%t", test.tol, got, test.want)
+		}
+	}
+
+	if p, _ := panics(func() { IsPSD(NewSymDense(2, nil), -1, nil) }); !p {
+		t.Error("expected panic for negative tolerance")
+	}
+}
+
+func TestLogAbsDetEstimate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 40
+
+	// A low-rank matrix plus the identity has clustered trailing singular values.
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = 1
+	}
+	s[0], s[1], s[2] = 50, 20, 10
+	a := NewTestMatrix(n, n, s, rnd)
+	want, _ := LogDet(a)
+	for _, rank := range []int{3, 8, n} {
+		got, ok := LogAbsDetEstimate(a, rank, rnd)
+		if !ok {
+			t.Fatalf("unexpected failure for rank %d", rank)
+		}
+		if math.Abs(got-want) > 1e-3 {
+			t.Errorf("unexpected estimate for rank %d: got:%v want:%v", rank, got, want)
+		}
+	}
+
+	// Spread trailing singular values are overestimated.
+	for i := 3; i < n; i++ {
+		s[i] = float64(n-i) / n
+	}
+	b := NewTestMatrix(n, n, s, rnd)
+	want, _ = LogDet(b)
+	if got, ok := LogAbsDetEstimate(b, 3, rnd); !ok || got < want {
+		t.Errorf("expected overestimate: got:%v ok:%t want at least:%v", got, ok, want)
+	}
+
+	// Singular matrices are detected.
+	for i := 3; i < n; i++ {
+		s[i] = 0
+	}
+	c := NewTestMatrix(n, n, s, rnd)
+	for _, rank := range []int{3, n} {
+		if _, ok := LogAbsDetEstimate(c, rank, rnd); ok {
+			t.Errorf("expected failure for singular matrix at rank %d", rank)
+		}
+	}
+
+	if p, _ := panics(func() { LogAbsDetEstimate(NewDense(3, 4, nil), 2, rnd) }); !p {
+		t.Error("expected panic for non-square matrix")
+	}
+	if p, _ := panics(func() { LogAbsDetEstimate(a, n+1, rnd) }); !p {
+		t.Error("expected panic for too large rank")
+	}
+}
+
+func TestStableRank(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 60, 40
+	for _, s := range [][]float64{
+		{1},
+		{2, 2, 2, 2},
+		{10, 5, 1, 0.5, 0.1},
+	} {
+		a := NewTestMatrix(m, n, s, rnd)
+		var frob float64
+		for _, v := range s {
+			frob += v * v
+		}
+		want := frob / (s[0] * s[0])
+		got := StableRank(a, 10, rnd)
+		if math.Abs(got-want) > 0.25*want {
+			t.Errorf("unexpected stable rank for spectrum %v: got:%v want:%v", s, got, want)
+		}
+	}
+	if got := StableRank(NewDense(m, n, nil), 3, rnd); got != 0 {
+		t.Errorf("unexpected stable rank of zero matrix: %v", got)
+	}
+	if p, _ := panics(func() { StableRank(NewDense(m, n, nil), n+1, rnd) }); !p {
+		t.Error("expected panic for too large rank")
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// idOversample is the oversampling used by the randomized sketches
+// of the interpolative decompositions.
+const idOversample = 10
+
+// TwoSidedID is a type for creating and using the randomized two-sided
+// interpolative decomposition of a matrix. The two-sided interpolative
+// decomposition of an m×n matrix A with rank k is
+//  A ≈ P_r A[J_r, J_c] P_c
+// where J_r and J_c index k rows and k columns of A, P_r is an m×k row
+// interpolation matrix and P_c is a k×n column interpolation matrix. The
+// selected skeleton A[J_r, J_c] consists of actual elements of A.
+type TwoSidedID struct {
+	rows, cols []int
+	pr, pc     *Dense
+}
+
+// Factorize computes the rank-k two-sided interpolative decomposition of a.
+// The columns are selected by a column-pivoted QR factorization of a random
+// sketch of a, and the rows are then selected by a column-pivoted QR
+// factorization of the transpose of the selected columns. If rnd is nil,
+// the global rand source is used.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if the numerical rank of a is less than k. If the decomposition
+// failed, routines that require a successful factorization will panic.
+// Factorize will panic if k is not in [1, min(m,n)].
+func (id *TwoSidedID) Factorize(a Matrix, k int, rnd *rand.Rand) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	id.rows, id.cols = nil, nil
+	id.pr, id.pc = nil, nil
+
+	cols, pc, ok := randColumnID(a, k, rnd)
+	if !ok {
+		return false
+	}
+	c := NewDense(m, k, nil)
+	for j, cj := range cols {
+		for i := 0; i < m; i++ {
+			c.set(i, j, a.At(i, cj))
+		}
+	}
+	rows, w, ok := columnID(c.T(), k)
+	if !ok {
+		return false
+	}
+	id.rows, id.cols = rows, cols
+	id.pr, id.pc = DenseCopyOf(w.T()), pc
+	return true
+}
+
+func (id *TwoSidedID) succFact() bool {
+	return id.pc != nil
+}
+
+// Rows returns the indices of the rows of A selected by the decomposition.
+//
+// Rows will panic if the receiver does not contain a successful factorization.
+func (id *TwoSidedID) Rows() []int {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), id.rows...)
+}
+
+// Columns returns the indices of the columns of A selected by the decomposition.
+//
+// Columns will panic if the receiver does not contain a successful factorization.
+func (id *TwoSidedID) Columns() []int {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), id.cols...)
+}
+
+// RowInterpTo extracts the m×k row interpolation matrix P_r into dst.
+//
+// If dst is empty, RowInterpTo will resize dst to be m×k. When dst is
+// non-empty, RowInterpTo will panic if dst is not m×k. RowInterpTo will also
+// panic if the receiver does not contain a successful factorization.
+func (id *TwoSidedID) RowInterpTo(dst *Dense) {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(id.pr.Dims())
+	dst.Copy(id.pr)
+}
+
+// ColInterpTo extracts the k×n column interpolation matrix P_c into dst.
+//
+// If dst is empty, ColInterpTo will resize dst to be k×n. When dst is
+// non-empty, ColInterpTo will panic if dst is not k×n. ColInterpTo will also
+// panic if the receiver does not contain a successful factorization.
+func (id *TwoSidedID) ColInterpTo(dst *Dense) {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(id.pc.Dims())
+	dst.Copy(id.pc)
+}
+
+// ColumnID is a type for creating and using the randomized column
+// interpolative decomposition of a matrix. The column interpolative
+// decomposition of an m×n matrix A with rank k is
+//  A ≈ A[:, J] X
+// where J indexes k columns of A and X is a k×n interpolation matrix that
+// contains the k×k identity in the selected columns. The selected columns
+// A[:, J] are actual columns of A, so they retain the structure of A.
+type ColumnID struct {
+	cols []int
+	x    *Dense
+}
+
+// Factorize computes the rank-k column interpolative decomposition of a. The
+// columns are selected by a column-pivoted QR factorization of a Gaussian
+// sketch G A with k+10 rows, or of a itself if a has no more rows, and X is
+// computed from the triangular factor of that factorization. If rnd is nil,
+// the global rand source is used.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if the numerical rank of a is less than k. If the decomposition
+// failed, routines that require a successful factorization will panic.
+// Factorize will panic if k is not in [1, min(m,n)].
+func (id *ColumnID) Factorize(a Matrix, k int, rnd *rand.Rand) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	id.cols, id.x = nil, nil
+	cols, x, ok := randColumnID(a, k, rnd)
+	if !ok {
+		return false
+	}
+	id.cols, id.x = cols, x
+	return true
+}
+
+func (id *ColumnID) succFact() bool {
+	return id.x != nil
+}
+
+// Columns returns the indices of the columns of A selected by the decomposition,
+// in the order corresponding to the rows of X.
+//
+// Columns will panic if the receiver does not contain a successful factorization.
+func (id *ColumnID) Columns() []int {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), id.cols...)
+}
+
+// InterpTo extracts the k×n interpolation matrix X into dst.
+//
+// If dst is empty, InterpTo will resize dst to be k×n. When dst is non-empty,
+// InterpTo will panic if dst is not k×n. InterpTo will also panic if the
+// receiver does not contain a successful factorization.
+func (id *ColumnID) InterpTo(dst *Dense) {
+	if !id.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(id.x.Dims())
+	dst.Copy(id.x)
+}
+
+// randColumnID returns a rank-k column interpolative decomposition of a,
+// a ≈ a[:, cols]·x, with the columns selected from a Gaussian sketch of a.
+func randColumnID(a Matrix, k int, rnd *rand.Rand) (cols []int, x *Dense, ok bool) {
+	m, _ := a.Dims()
+	l := min(k+idOversample, m)
+	if l == m {
+		return columnID(a, k)
+	}
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+	g := NewDense(l, m, nil)
+	for i := range g.mat.Data {
+		g.mat.Data[i] = normFloat64()
+	}
+	var y Dense
+	y.Mul(g, a)
+	return columnID(&y, k)
+}
+
+// columnID returns a rank-k column interpolative decomposition of a,
+// a ≈ a[:, cols]·x, computed deterministically from a column-pivoted QR
+// factorization of a. The k×n matrix x contains the k×k identity in the
+// selected columns. columnID returns false if the leading k×k block of the
+// pivoted triangular factor is numerically singular, that is if one of its
+// diagonal elements is at most max(m,n)·ε times the largest, |R[0,0]|, in
+// magnitude.
+func columnID(a Matrix, k int) (cols []int, x *Dense, ok bool) {
+	m, n := a.Dims()
+	r, piv := pivotedQR(a, k)
+
+	// Solve R₁₁ T = R₁₂ for the interpolation coefficients.
+	tol := float64(max(m, n)) * (1.0 / (1 << 53)) * math.Abs(r.at(0, 0))
+	for i := 0; i < k; i++ {
+		if math.Abs(r.at(i, i)) <= tol {
+			return nil, nil, false
+		}
+	}
+	x = NewDense(k, n, nil)
+	for j := 0; j < k; j++ {
+		x.set(j, piv[j], 1)
+	}
+	if k < n {
+		t := DenseCopyOf(r.slice(0, k, k, n))
+		blas64.Trsm(blas.Left, blas.NoTrans, 1, blas64.Triangular{
+			Uplo:   blas.Upper,
+			Diag:   blas.NonUnit,
+			N:      k,
+			Data:   r.mat.Data,
+			Stride: r.mat.Stride,
+		}, t.mat)
+		for j := k; j < n; j++ {
+			for i := 0; i < k; i++ {
+				x.set(i, piv[j], t.at(i, j-k))
+			}
+		}
+	}
+	return piv[:k:k], x, true
+}
+
+// pivotedQR performs k steps of the Householder QR factorization of a with
+// column pivoting, selecting at each step the remaining column with largest
+// norm. It returns the leading k rows of the triangular factor R, with its
+// columns in pivoted order, and the column permutation piv such that column
+// j of R corresponds to column piv[j] of a.
+func pivotedQR(a Matrix, k int) (r *Dense, piv []int) {
+	w := DenseCopyOf(a)
+	m, n := w.Dims()
+	piv = make([]int, n)
+	for j := range piv {
+		piv[j] = j
+	}
+	v := make([]float64, m)
+	for j := 0; j < k; j++ {
+		// Select the pivot column and move it into position j.
+		p, best := j, -1.0
+		for c := j; c < n; c++ {
+			var ss float64
+			for i := j; i < m; i++ {
+				e := w.at(i, c)
+				ss += e * e
+			}
+			if ss > best {
+				p, best = c, ss
+			}
+		}
+		if p != j {
+			piv[j], piv[p] = piv[p], piv[j]
+			for i := 0; i < m; i++ {
+				row := w.rawRowView(i)
+				row[j], row[p] = row[p], row[j]
+			}
+		}
+
+		// Annihilate the subdiagonal of column j.
+		norm := math.Sqrt(best)
+		if norm == 0 {
+			continue
+		}
+		alpha := -math.Copysign(norm, w.at(j, j))
+		vec := v[j:]
+		for i := range vec {
+			vec[i] = w.at(j+i, j)
+		}
+		vec[0] -= alpha
+		var vv float64
+		for _, e := range vec {
+			vv += e * e
+		}
+		for c := j + 1; c < n; c++ {
+			var s float64
+			for i, e := range vec {
+				s += e * w.at(j+i, c)
+			}
+			s *= 2 / vv
+			for i, e := range vec {
+				w.set(j+i, c, w.at(j+i, c)-s*e)
+			}
+		}
+		w.set(j, j, alpha)
+		for i := j + 1; i < m; i++ {
+			w.set(i, j, 0)
+		}
+	}
+	return w.slice(0, k, 0, n), piv
+}
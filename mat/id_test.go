@@ -0,0 +1,172 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randLowRank returns a random m×n matrix of rank k.
+func randLowRank(m, n, k int, rnd *rand.Rand) *Dense {
+	l := NewDense(m, k, nil)
+	for i := range l.mat.Data {
+		l.mat.Data[i] = rnd.NormFloat64()
+	}
+	r := NewDense(k, n, nil)
+	for i := range r.mat.Data {
+		r.mat.Data[i] = rnd.NormFloat64()
+	}
+	var a Dense
+	a.Mul(l, r)
+	return &a
+}
+
+// isIndexSet returns whether idx holds distinct indices in [0, n).
+func isIndexSet(idx []int, n int) bool {
+	s := append([]int(nil), idx...)
+	sort.Ints(s)
+	for i, v := range s {
+		if v < 0 || n <= v || (i > 0 && s[i-1] == v) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTwoSidedID(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 10, n: 8, k: 3},
+		{m: 40, n: 30, k: 5},
+		{m: 25, n: 60, k: 12},
+		{m: 6, n: 6, k: 6},
+	} {
+		a := randLowRank(test.m, test.n, test.k, rnd)
+
+		var id TwoSidedID
+		if !id.Factorize(a, test.k, rnd) {
+			t.Fatalf("unexpected factorization failure for %d×%d rank %d", test.m, test.n, test.k)
+		}
+		rows := id.Rows()
+		cols := id.Columns()
+		if len(rows) != test.k || !isIndexSet(rows, test.m) {
+			t.Errorf("invalid row selection for %d×%d: %v", test.m, test.n, rows)
+		}
+		if len(cols) != test.k || !isIndexSet(cols, test.n) {
+			t.Errorf("invalid column selection for %d×%d: %v", test.m, test.n, cols)
+		}
+
+		skel := NewDense(test.k, test.k, nil)
+		for i, r := range rows {
+			for j, c := range cols {
+				skel.Set(i, j, a.At(r, c))
+			}
+		}
+		var pr, pc, got Dense
+		id.RowInterpTo(&pr)
+		id.ColInterpTo(&pc)
+		got.Product(&pr, skel, &pc)
+		if !EqualApprox(&got, a, 1e-8) {
+			t.Errorf("unexpected reconstruction for %d×%d rank %d", test.m, test.n, test.k)
+		}
+
+		// The interpolation matrices must contain the identity
+		// in the selected rows and columns.
+		for i, r := range rows {
+			for j, c := range cols {
+				want := 0.0
+				if i == j {
+					want = 1
+				}
+				if pr.At(r, j) != want || pc.At(i, c) != want {
+					t.Errorf("interpolation matrices do not interpolate skeleton for %d×%d", test.m, test.n)
+				}
+			}
+		}
+	}
+
+	var id TwoSidedID
+	if id.Factorize(NewDense(5, 5, nil), 2, rnd) {
+		t.Error("unexpected success for zero matrix")
+	}
+	for _, k := range []int{3, 4, 10} {
+		if id.Factorize(randLowRank(30, 20, 2, rnd), k, rnd) {
+			t.Errorf("unexpected success for rank 2 matrix with k=%d", k)
+		}
+	}
+}
+
+func TestColumnID(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 10, n: 8, k: 3},
+		{m: 40, n: 30, k: 5},
+		{m: 25, n: 60, k: 12},
+		{m: 6, n: 6, k: 6},
+	} {
+		a := randLowRank(test.m, test.n, test.k, rnd)
+
+		var id ColumnID
+		if !id.Factorize(a, test.k, rnd) {
+			t.Fatalf("unexpected factorization failure for %d×%d rank %d", test.m, test.n, test.k)
+		}
+		cols := id.Columns()
+		if len(cols) != test.k || !isIndexSet(cols, test.n) {
+			t.Errorf("invalid column selection for %d×%d: %v", test.m, test.n, cols)
+		}
+
+		c := NewDense(test.m, test.k, nil)
+		for j, cj := range cols {
+			for i := 0; i < test.m; i++ {
+				c.Set(i, j, a.At(i, cj))
+			}
+		}
+		var x, got Dense
+		id.InterpTo(&x)
+		got.Mul(c, &x)
+		if !EqualApprox(&got, a, 1e-8) {
+			t.Errorf("unexpected reconstruction for %d×%d rank %d", test.m, test.n, test.k)
+		}
+		for i := range cols {
+			for j, cj := range cols {
+				want := 0.0
+				if i == j {
+					want = 1
+				}
+				if x.At(i, cj) != want {
+					t.Errorf("interpolation matrix does not contain identity for %d×%d", test.m, test.n)
+				}
+			}
+		}
+	}
+
+	var id ColumnID
+	if id.Factorize(NewDense(5, 5, nil), 2, rnd) {
+		t.Error("unexpected success for zero matrix")
+	}
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 30, n: 20, k: 4},
+		{m: 8, n: 40, k: 3},
+		{m: 12, n: 12, k: 12},
+	} {
+		if id.Factorize(randLowRank(test.m, test.n, 2, rnd), test.k, rnd) {
+			t.Errorf("unexpected success for rank 2 %d×%d matrix with k=%d", test.m, test.n, test.k)
+		}
+	}
+	if p, _ := panics(func() { id.Factorize(NewDense(5, 4, nil), 5, rnd) }); !p {
+		t.Error("expected panic for rank greater than min(m,n)")
+	}
+}
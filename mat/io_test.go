@@ -21,6 +21,9 @@ var (
 	_ encoding.BinaryUnmarshaler = (*Dense)(nil)
 	_ encoding.BinaryMarshaler   = (*VecDense)(nil)
 	_ encoding.BinaryUnmarshaler = (*VecDense)(nil)
+
+	_ io.WriterTo   = (*Dense)(nil)
+	_ io.ReaderFrom = (*Dense)(nil)
 )
 
 var sizeInt64 = binary.Size(int64(0))
@@ -306,6 +309,58 @@ func TestDenseIORoundTrip(t *testing.T) {
 	}
 }
 
+func TestDenseWriteToReadFrom(t *testing.T) {
+	t.Parallel()
+	for i, test := range denseData {
+		// The streamed form must match MarshalBinary.
+		want, err := test.want.MarshalBinary()
+		if err != nil {
+			t.Errorf("error encoding test #%d: %v", i, err)
+			continue
+		}
+		var buf bytes.Buffer
+		n, err := test.want.WriteTo(&buf)
+		if err != nil {
+			t.Errorf("error writing test #%d: %v", i, err)
+			continue
+		}
+		if n != int64(len(want)) {
+			t.Errorf("unexpected number of bytes written for test #%d: got:%d want:%d", i, n, len(want))
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("encoding via WriteTo and MarshalBinary differ for test #%d", i)
+		}
+
+		// Follow the matrix with extra data that must not be consumed.
+		buf.WriteString("tail")
+		var got Dense
+		n, err = got.ReadFrom(&buf)
+		if err != nil {
+			if err != test.err {
+				t.Errorf("error reading test #%d: %v", i, err)
+			}
+			continue
+		}
+		if n != int64(len(want)) {
+			t.Errorf("unexpected number of bytes read for test #%d: got:%d want:%d", i, n, len(want))
+		}
+		if !test.eq(&got, test.want) {
+			t.Errorf("r/w test #%d failed\n got=%#v\nwant=%#v", i, &got, test.want)
+		}
+		if buf.String() != "tail" {
+			t.Errorf("unexpected data consumed for test #%d", i)
+		}
+	}
+
+	// Truncated input is reported.
+	raw := denseData[1].raw
+	var got Dense
+	_, err := got.ReadFrom(bytes.NewReader(raw[:len(raw)-3]))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("unexpected error for truncated input: got:%v want:%v", err, io.ErrUnexpectedEOF)
+	}
+}
+
 var vectorData = []struct {
 	raw  []byte
 	want *VecDense
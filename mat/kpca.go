@@ -0,0 +1,115 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// kpcaOversample is the number of additional sketch columns
+// used by the randomized kernel principal component analysis.
+const kpcaOversample = 10
+
+// KernelPCA performs a randomized kernel principal component analysis of the
+// n rows of X. The symmetric n×n kernel matrix K is defined implicitly by
+// kernel, where kernel(i, j) is the kernel evaluated on rows i and j of X, and
+// is centered in feature space as
+//  Kc = H K H,  H = I - 1 1ᵀ/n.
+// KernelPCA returns the n×components matrix of the coordinates of the rows of
+// X along the leading principal components, U·sqrt(Λ), and the corresponding
+// leading eigenvalues Λ of Kc in descending order. Coordinates along
+// components with non-positive eigenvalues are zero.
+//
+// The eigendecomposition is computed from a randomized sketch of Kc with
+// components+10 columns, and K is never formed: each of the two passes over K
+// evaluates one row of the kernel at a time, so KernelPCA requires O(n) kernel
+// evaluations per row and O(n·(components+10)) memory in total, rather than the
+// O(n²) memory needed to store K. The price is that every element of K is
+// evaluated twice. When kernel evaluations are expensive and K fits in memory,
+// forming K explicitly and using EigenSym may be faster. X itself is only used
+// for its number of rows. If rnd is nil, the global rand source is used.
+//
+// KernelPCA will panic if components is not in [1, n]. KernelPCA returns
+// ErrFailedEigen if the eigendecomposition of the projected kernel fails.
+func KernelPCA(X Matrix, kernel func(i, j int) float64, components int, rnd *rand.Rand) (*Dense, []float64, error) {
+	n, _ := X.Dims()
+	if components < 1 || n < components {
+		panic(ErrShape)
+	}
+	l := min(components+kpcaOversample, n)
+
+	// Sketch the range of the centered kernel, Kc Ω = H (K (H Ω)).
+	omega := makeRandomMatrix(n, l, distNormal, rnd)
+	omega.CenterColumns(omega)
+	z := NewDense(n, l, nil)
+	kernelMul(z, kernel, omega)
+	z.CenterColumns(z)
+
+	q := orthonormalBasis(z)
+	// The columns of Q are in the range of H, so they
+	// sum to zero and Qᵀ Kc Q = Qᵀ K Q.
+	q.CenterColumns(q)
+
+	kq := NewDense(n, l, nil)
+	kernelMul(kq, kernel, q)
+	var t Dense
+	t.Mul(q.T(), kq)
+	b := NewSymDense(l, nil)
+	for i := 0; i < l; i++ {
+		for j := i; j < l; j++ {
+			b.SetSym(i, j, 0.5*(t.at(i, j)+t.at(j, i)))
+		}
+	}
+
+	var eig EigenSym
+	if !eig.Factorize(b, true) {
+		return nil, nil, ErrFailedEigen
+	}
+	lambda := eig.Values(nil)
+	var w Dense
+	eig.VectorsTo(&w)
+	perm := make([]int, l)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool { return lambda[perm[i]] > lambda[perm[j]] })
+
+	wk := NewDense(l, components, nil)
+	values := make([]float64, components)
+	for j, pj := range perm[:components] {
+		values[j] = lambda[pj]
+		scale := 0.0
+		if lambda[pj] > 0 {
+			scale = math.Sqrt(lambda[pj])
+		}
+		for i := 0; i < l; i++ {
+			wk.set(i, j, scale*w.at(i, pj))
+		}
+	}
+	coords := NewDense(n, components, nil)
+	coords.Mul(q, wk)
+	return coords, values, nil
+}
+
+// kernelMul computes dst = K b for the symmetric kernel matrix K defined by
+// kernel, evaluating one row of K at a time.
+func kernelMul(dst *Dense, kernel func(i, j int) float64, b *Dense) {
+	n, _ := b.Dims()
+	row := getFloats(n, false)
+	defer putFloats(row)
+	for i := 0; i < n; i++ {
+		for j := range row {
+			row[j] = kernel(i, j)
+		}
+		blas64.Gemv(blas.Trans, 1, b.mat, blas64.Vector{N: n, Inc: 1, Data: row},
+			0, blas64.Vector{N: dst.mat.Cols, Inc: 1, Data: dst.rawRowView(i)})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestKernelPCA(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// With a linear kernel, kernel PCA is PCA of the centered data,
+	// so the eigenvalues are the squared singular values of the
+	// centered data and the coordinates have the same magnitudes.
+	const n, d = 60, 3
+	x := NewRandomNormalDense(n, d, rnd)
+	for j := 0; j < d; j++ {
+		for i := 0; i < n; i++ {
+			x.set(i, j, x.at(i, j)*float64(d-j))
+		}
+	}
+	linear := func(i, j int) float64 {
+		return Dot(x.RowView(i), x.RowView(j))
+	}
+	coords, values, err := KernelPCA(x, linear, d, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var xc Dense
+	xc.CenterColumns(x)
+	var svd SVD
+	if !svd.Factorize(&xc, SVDThin) {
+		t.Fatal("unexpected SVD failure")
+	}
+	s := svd.Values(nil)
+	var u Dense
+	svd.UTo(&u)
+	for j := 0; j < d; j++ {
+		if math.Abs(values[j]-s[j]*s[j]) > 1e-8*s[0]*s[0] {
+			t.Errorf("unexpected linear kernel eigenvalue %d: got:%v want:%v", j, values[j], s[j]*s[j])
+		}
+		for i := 0; i < n; i++ {
+			got, want := math.Abs(coords.At(i, j)), math.Abs(s[j]*u.At(i, j))
+			if math.Abs(got-want) > 1e-8*s[0] {
+				t.Errorf("unexpected linear kernel coordinate (%d,%d): got:%v want:%v", i, j, got, want)
+			}
+		}
+	}
+
+	// When the sketch spans the whole space the eigenvalues
+	// must match those of the explicitly centered kernel.
+	const small, comps = 12, 2
+	y := NewRandomNormalDense(small, 2, rnd)
+	rbf := func(i, j int) float64 {
+		var diff VecDense
+		diff.SubVec(y.RowView(i), y.RowView(j))
+		return math.Exp(-Dot(&diff, &diff) / 2)
+	}
+	_, values, err = KernelPCA(y, rbf, comps, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k := NewSymDense(small, nil)
+	for i := 0; i < small; i++ {
+		for j := i; j < small; j++ {
+			k.SetSym(i, j, rbf(i, j))
+		}
+	}
+	h := NewDense(small, small, nil)
+	for i := 0; i < small; i++ {
+		for j := 0; j < small; j++ {
+			h.set(i, j, -1.0/small)
+		}
+		h.set(i, i, 1-1.0/small)
+	}
+	var kc Dense
+	kc.Product(h, k, h)
+	var eig EigenSym
+	if !eig.Factorize(NewSymDense(small, kc.mat.Data), false) {
+		t.Fatal("unexpected eigendecomposition failure")
+	}
+	want := eig.Values(nil)
+	for j := 0; j < comps; j++ {
+		w := want[small-1-j]
+		if math.Abs(values[j]-w) > 1e-10 {
+			t.Errorf("unexpected RBF kernel eigenvalue %d: got:%v want:%v", j, values[j], w)
+		}
+	}
+
+	if p, _ := panics(func() { KernelPCA(y, rbf, small+1, rnd) }); !p {
+		t.Error("expected panic for too many components")
+	}
+}
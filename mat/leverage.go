@@ -0,0 +1,74 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "golang.org/x/exp/rand"
+
+// LeverageScores returns the rank-k row and column leverage scores of the m×n
+// matrix A, which are the squared Euclidean norms of the rows of U_k and V_k,
+// where U_k and V_k hold the leading k left and right singular vectors of A.
+// The scores lie in [0, 1] and each set sums to k. They measure the influence
+// of each row and column on the best rank-k approximation of A, and are the
+// sampling probabilities, after division by k, of CUR decompositions,
+// randomized regression and other importance sampling methods.
+//
+// LeverageScores computes the thin singular value decomposition of A, and it
+// returns ErrFailedSVD if the decomposition fails. LeverageScoresEstimate is
+// considerably cheaper for large matrices. LeverageScores will panic if k is
+// not in [1, min(m,n)].
+func LeverageScores(A Matrix, k int) (rows, cols []float64, err error) {
+	m, n := A.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	var svd SVD
+	if !svd.Factorize(A, SVDThin) {
+		return nil, nil, ErrFailedSVD
+	}
+	var u, v Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rows = leverageScores(u.slice(0, m, 0, k))
+	cols = leverageScores(v.slice(0, n, 0, k))
+	return rows, cols, nil
+}
+
+// LeverageScoresEstimate returns estimates of the rank-k row and column
+// leverage scores of the m×n matrix A computed from the singular vectors of
+// a randomized singular value decomposition of A with an oversampling of 10
+// and one power iteration, using rnd as the source of randomness. If rnd is
+// nil, the global rand source is used. The estimates are exact if A has rank
+// at most k, and otherwise approximate the scores of LeverageScores to the
+// accuracy with which the randomized factors capture the leading singular
+// subspaces. The cost is O(m·n·k) rather than that of a full SVD.
+//
+// LeverageScoresEstimate returns ErrFailedSVD if the decomposition fails. It
+// will panic if k is not in [1, min(m,n)].
+func LeverageScoresEstimate(A Matrix, k int, rnd *rand.Rand) (rows, cols []float64, err error) {
+	m, n := A.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(A, k, withRand(rnd), RSVDOversample(10), RSVDPowerIter(1)) {
+		return nil, nil, ErrFailedSVD
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	return leverageScores(&u), leverageScores(&v), nil
+}
+
+// leverageScores returns the squared Euclidean norms of the rows of q.
+func leverageScores(q *Dense) []float64 {
+	r, _ := q.Dims()
+	scores := make([]float64, r)
+	for i := range scores {
+		for _, v := range q.rawRowView(i) {
+			scores[i] += v * v
+		}
+	}
+	return scores
+}
@@ -0,0 +1,75 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestLeverageScores(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 30, n: 20, k: 3},
+		{m: 20, n: 40, k: 5},
+		{m: 10, n: 10, k: 10},
+	} {
+		s := []float64{10, 8, 6, 4, 2, 1, 0.5, 0.25, 0.1, 0.05}
+		a := NewTestMatrix(test.m, test.n, s[:min(len(s), min(test.m, test.n))], rnd)
+		rows, cols, err := LeverageScores(a, test.k)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rows) != test.m || len(cols) != test.n {
+			t.Fatalf("unexpected lengths: %d, %d", len(rows), len(cols))
+		}
+		for _, scores := range [][]float64{rows, cols} {
+			if sum := floats.Sum(scores); math.Abs(sum-float64(test.k)) > 1e-10 {
+				t.Errorf("%d×%d k=%d: scores sum to %v", test.m, test.n, test.k, sum)
+			}
+			for _, v := range scores {
+				if v < 0 || 1+1e-12 < v {
+					t.Errorf("%d×%d k=%d: score %v out of range", test.m, test.n, test.k, v)
+				}
+			}
+		}
+
+		// For a matrix of rank k the estimates are exact.
+		low := randLowRank(test.m, test.n, test.k, rnd)
+		wantRows, wantCols, err := LeverageScores(low, test.k)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		gotRows, gotCols, err := LeverageScoresEstimate(low, test.k, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !floats.EqualApprox(gotRows, wantRows, 1e-8) || !floats.EqualApprox(gotCols, wantCols, 1e-8) {
+			t.Errorf("%d×%d k=%d: unexpected estimated scores", test.m, test.n, test.k)
+		}
+	}
+
+	// A full column rank tall matrix has column scores of one.
+	a := NewRandomNormalDense(12, 4, rnd)
+	_, cols, err := LeverageScores(a, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(cols, []float64{1, 1, 1, 1}, 1e-12) {
+		t.Errorf("unexpected column scores for full rank matrix: %v", cols)
+	}
+	for _, k := range []int{0, 5} {
+		if p, _ := panics(func() { LeverageScores(a, k) }); !p {
+			t.Errorf("expected panic for k=%d", k)
+		}
+	}
+}
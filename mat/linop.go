@@ -0,0 +1,207 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+// LinearOp is a linear operator that is only accessed through its products
+// with vectors. It allows matrix-free methods to work with operators that are
+// defined implicitly, for example by a fast transform, without forming them.
+// BandDense and SymBandDense implement LinearOp, and NewMatrixOp adapts any
+// Matrix.
+type LinearOp interface {
+	// Dims returns the dimensions of the operator.
+	Dims() (r, c int)
+
+	// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+	MulVecTo(dst *VecDense, trans bool, x Vector)
+}
+
+// NewMatrixOp returns a LinearOp that computes its products with a.
+func NewMatrixOp(a Matrix) LinearOp {
+	return matrixOp{a}
+}
+
+type matrixOp struct {
+	a Matrix
+}
+
+func (op matrixOp) Dims() (r, c int) { return op.a.Dims() }
+
+func (op matrixOp) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	if trans {
+		dst.MulVec(op.a.T(), x)
+		return
+	}
+	dst.MulVec(op.a, x)
+}
+
+// NewLowRankPlusDiagOp returns the m×n LinearOp
+//  A = D + U Vᵀ,
+// where D is the m×n diagonal matrix with the elements of d on its main
+// diagonal, U is m×k and V is n×k. The products with A and Aᵀ are computed
+// in O((m+n)·k) operations and A is never formed, so that, for example,
+// RSVD.FactorizeOp can factorize a low-rank update of a diagonal matrix in
+// time linear in its dimensions. The operator retains u, v and d without
+// copying them.
+//
+// NewLowRankPlusDiagOp will panic if u and v do not have the same number of
+// columns, or if the length of d is not min(m,n).
+func NewLowRankPlusDiagOp(d []float64, u, v *Dense) LinearOp {
+	m, ku := u.Dims()
+	n, kv := v.Dims()
+	if ku != kv {
+		panic(ErrShape)
+	}
+	if len(d) != min(m, n) {
+		panic(ErrShape)
+	}
+	return lowRankPlusDiagOp{d: d, u: u, v: v}
+}
+
+type lowRankPlusDiagOp struct {
+	d    []float64
+	u, v *Dense
+}
+
+func (op lowRankPlusDiagOp) Dims() (r, c int) {
+	r, _ = op.u.Dims()
+	c, _ = op.v.Dims()
+	return r, c
+}
+
+func (op lowRankPlusDiagOp) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	u, v := op.u, op.v
+	if trans {
+		u, v = v, u
+	}
+	m, _ := u.Dims()
+	n, _ := v.Dims()
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.reuseAsNonZeroed(m)
+	} else if dst.Len() != m {
+		panic(ErrShape)
+	}
+
+	// [y] = [D x + U (Vᵀ x)]
+	var w, y VecDense
+	w.MulVec(v.T(), x)
+	y.MulVec(u, &w)
+	for i, di := range op.d {
+		y.setVec(i, y.at(i)+di*x.AtVec(i))
+	}
+	dst.CopyVec(&y)
+}
+
+// deflatedOp is the operator a - u vᵀ returned by RSVD.DeflatedOp.
+type deflatedOp struct {
+	a    Matrix
+	u, v *Dense
+}
+
+func (op deflatedOp) Dims() (r, c int) { return op.a.Dims() }
+
+func (op deflatedOp) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	a, u, v := op.a, op.u, op.v
+	if trans {
+		a, u, v = a.T(), v, u
+	}
+	// [y] = [A x - U (Vᵀ x)]
+	var w, y VecDense
+	w.MulVec(v.T(), x)
+	y.MulVec(u, &w)
+	dst.MulVec(a, x)
+	dst.SubVec(dst, &y)
+}
+
+// mulOp places the product of op, or of its transpose if trans is true,
+// with the columns of b into the corresponding columns of dst.
+func mulOp(dst *Dense, op LinearOp, trans bool, b *Dense) {
+	r, c := op.Dims()
+	if trans {
+		r, c = c, r
+	}
+	br, k := b.Dims()
+	if br != c {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(r, k)
+	for j := 0; j < k; j++ {
+		op.MulVecTo(dst.ColView(j).(*VecDense), trans, b.ColView(j))
+	}
+}
+
+// CGLS solves the least-squares problems
+//  min_x ‖A x - b‖₂
+// for each column of the m×k matrix b by the conjugate gradient method applied
+// to the normal equations, storing the solutions in the columns of dst. A is
+// only accessed through op, with one product with A and one with Aᵀ per
+// iteration, and AᵀA is never formed. The iteration for a column starts from
+// zero and stops when the norm of the normal equations residual Aᵀ(b - A x)
+// is at most tol times its initial value Aᵀb, or after maxIter iterations.
+//
+// CGLS returns the Frobenius norm of the final residual b - A X and the largest
+// number of iterations used for any column. If any column did not converge
+// within maxIter iterations, CGLS returns ErrIterationLimit with the
+// approximate solutions still stored in dst.
+//
+// If dst is empty, CGLS will resize dst to be n×k. When dst is non-empty, CGLS
+// will panic if dst is not n×k. CGLS will panic if b does not have m rows, if
+// maxIter is less than one or if tol is negative.
+func CGLS(dst *Dense, op LinearOp, b Matrix, maxIter int, tol float64) (residual float64, iterations int, err error) {
+	m, n := op.Dims()
+	bm, k := b.Dims()
+	if bm != m {
+		panic(ErrShape)
+	}
+	if maxIter < 1 {
+		panic("mat: number of iterations must be positive")
+	}
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	dst.reuseAsZeroed(n, k)
+
+	r := NewVecDense(m, nil)
+	q := NewVecDense(m, nil)
+	s := NewVecDense(n, nil)
+	p := NewVecDense(n, nil)
+	x := NewVecDense(n, nil)
+	var ss float64
+	for col := 0; col < k; col++ {
+		for i := 0; i < m; i++ {
+			r.setVec(i, b.At(i, col))
+		}
+		x.Zero()
+		op.MulVecTo(s, true, r)
+		p.CopyVec(s)
+		gamma := Dot(s, s)
+		stop := tol * tol * gamma
+
+		var iter int
+		for ; iter < maxIter && gamma > stop && gamma != 0; iter++ {
+			op.MulVecTo(q, false, p)
+			alpha := gamma / Dot(q, q)
+			x.AddScaledVec(x, alpha, p)
+			r.AddScaledVec(r, -alpha, q)
+			op.MulVecTo(s, true, r)
+			gammaNew := Dot(s, s)
+			p.AddScaledVec(s, gammaNew/gamma, p)
+			gamma = gammaNew
+		}
+		if gamma > stop && gamma != 0 {
+			err = ErrIterationLimit
+		}
+		iterations = max(iterations, iter)
+		ss += Dot(r, r)
+		for i := 0; i < n; i++ {
+			dst.set(i, col, x.at(i))
+		}
+	}
+	return math.Sqrt(ss), iterations, err
+}
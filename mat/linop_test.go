@@ -0,0 +1,134 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCGLS(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, k = 40, 15, 3
+	a := NewRandomNormalDense(m, n, rnd)
+	b := NewRandomNormalDense(m, k, rnd)
+	band := randBandDense(m, n, 3, 2, rnd)
+
+	for _, test := range []struct {
+		name string
+		a    Matrix
+		op   LinearOp
+	}{
+		{name: "dense", a: a, op: NewMatrixOp(a)},
+		{name: "transposed", a: a.T().T(), op: NewMatrixOp(a.T().T())},
+		{name: "band", a: band, op: band},
+	} {
+		var want Dense
+		var qr QR
+		qr.Factorize(DenseCopyOf(test.a))
+		if err := qr.SolveTo(&want, false, b); err != nil {
+			t.Fatalf("%s: unexpected QR error: %v", test.name, err)
+		}
+		var wantRes Dense
+		wantRes.Mul(test.a, &want)
+		wantRes.Sub(b, &wantRes)
+
+		var got Dense
+		res, iter, err := CGLS(&got, test.op, b, 10*n, 1e-12)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if iter < 1 || 10*n < iter {
+			t.Errorf("%s: unexpected iteration count: %d", test.name, iter)
+		}
+		if !EqualApprox(&got, &want, 1e-8) {
+			t.Errorf("%s: unexpected least-squares solution", test.name)
+		}
+		if want := Norm(&wantRes, 2); math.Abs(res-want) > 1e-8*want {
+			t.Errorf("%s: unexpected residual: got:%v want:%v", test.name, res, want)
+		}
+	}
+
+	// Stopping early reports the iteration limit.
+	var got Dense
+	_, iter, err := CGLS(&got, NewMatrixOp(a), b, 2, 1e-14)
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error for iteration limit: got:%v want:%v", err, ErrIterationLimit)
+	}
+	if iter != 2 {
+		t.Errorf("unexpected iteration count at limit: got:%d want:2", iter)
+	}
+
+	// A zero right-hand side needs no iterations.
+	got.Reset()
+	res, iter, err := CGLS(&got, NewMatrixOp(a), NewDense(m, 1, nil), 5, 1e-12)
+	if err != nil || iter != 0 || res != 0 {
+		t.Errorf("unexpected result for zero right-hand side: res=%v iter=%d err=%v", res, iter, err)
+	}
+}
+
+func TestLowRankPlusDiagOp(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 7, n: 7, k: 2},
+		{m: 9, n: 5, k: 3},
+		{m: 4, n: 8, k: 1},
+	} {
+		u := NewRandomNormalDense(test.m, test.k, rnd)
+		v := NewRandomNormalDense(test.n, test.k, rnd)
+		d := make([]float64, min(test.m, test.n))
+		for i := range d {
+			d[i] = rnd.NormFloat64()
+		}
+		op := NewLowRankPlusDiagOp(d, u, v)
+		if r, c := op.Dims(); r != test.m || c != test.n {
+			t.Errorf("unexpected dimensions: got:%d×%d want:%d×%d", r, c, test.m, test.n)
+		}
+
+		var a Dense
+		a.Mul(u, v.T())
+		for i, di := range d {
+			a.set(i, i, a.at(i, i)+di)
+		}
+		for _, trans := range []bool{false, true} {
+			var want Matrix = &a
+			c := test.n
+			if trans {
+				want = a.T()
+				c = test.m
+			}
+			x := NewVecDense(c, nil)
+			for i := 0; i < c; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			var got, wantVec VecDense
+			op.MulVecTo(&got, trans, x)
+			wantVec.MulVec(want, x)
+			if !EqualApprox(&got, &wantVec, 1e-12) {
+				t.Errorf("%d×%d rank %d trans=%t: unexpected product", test.m, test.n, test.k, trans)
+			}
+		}
+	}
+
+	u := NewDense(4, 2, nil)
+	for _, test := range []struct {
+		name string
+		d    []float64
+		v    *Dense
+	}{
+		{name: "columns", d: make([]float64, 3), v: NewDense(3, 1, nil)},
+		{name: "diagonal", d: make([]float64, 4), v: NewDense(3, 2, nil)},
+	} {
+		if p, _ := panics(func() { NewLowRankPlusDiagOp(test.d, u, test.v) }); !p {
+			t.Errorf("%s: expected panic for mismatched shape", test.name)
+		}
+	}
+}
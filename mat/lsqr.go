@@ -0,0 +1,199 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// sketchLeastSquaresOversample is the ratio of the number of rows of the
+// sketch used by SketchLeastSquares to the number of columns of A.
+const sketchLeastSquaresOversample = 4
+
+// SketchLeastSquares solves the overdetermined least-squares problems
+//  min_x ‖A x - b‖₂
+// for each column of the m×k matrix b, where A is m×n with m ≥ n and full
+// column rank, storing the solutions in the columns of dst. The solutions are
+// computed by sketch-and-precondition, following Avron, Maymounkov and Toledo,
+// "Blendenpik: Supercharging LAPACK's least-squares solver", SIAM J. Sci.
+// Comput. 32(3), 2010. The rows of A are mixed by a subsampled randomized
+// Hadamard transform S drawn from rnd to form the 4n×n sketch S A, and the
+// triangular factor R of the QR factorization of the sketch is used as a right
+// preconditioner for LSQR applied to
+//  min_y ‖A R⁻¹ y - b‖₂, x = R⁻¹ y.
+// With high probability A R⁻¹ is well conditioned independently of the
+// condition of A, so LSQR converges in a small number of iterations that does
+// not depend on A. The sketch costs O(n·N·log N) operations, where N is the
+// smallest power of two not less than m, and each iteration costs one product
+// with A and one with Aᵀ, so for m much larger than n the solver is faster
+// than the O(m·n²) of a dense QR factorization. If m is at most 4n, R is
+// computed from the QR factorization of A itself, and LSQR refines the
+// solution in few iterations.
+//
+// The iteration for a column stops when the estimated norm of the
+// preconditioned normal equations residual (A R⁻¹)ᵀ(b - A x) is at most tol
+// times the estimated norms of A R⁻¹ and of the residual, when the residual
+// norm is at most tol times the norm of the column of b, or after maxIter
+// iterations.
+//
+// SketchLeastSquares returns the Frobenius norm of the final residual b - A X
+// and the largest number of iterations used for any column. If any column did
+// not converge within maxIter iterations, SketchLeastSquares returns
+// ErrIterationLimit with the approximate solutions still stored in dst. If the
+// sketch is numerically rank deficient, which is the case if A does not have
+// full column rank, SketchLeastSquares returns ErrSingular and dst is zero.
+//
+// If dst is empty, SketchLeastSquares will resize dst to be n×k. When dst is
+// non-empty, SketchLeastSquares will panic if dst is not n×k. If rnd is nil,
+// the global rand source is used. SketchLeastSquares will panic if m is less
+// than n, if n is zero, if b does not have m rows, if maxIter is less than one
+// or if tol is negative.
+func SketchLeastSquares(dst *Dense, A, b Matrix, maxIter int, tol float64, rnd *rand.Rand) (residual float64, iterations int, err error) {
+	m, n := A.Dims()
+	if m < n || n == 0 {
+		panic(ErrShape)
+	}
+	bm, k := b.Dims()
+	if bm != m {
+		panic(ErrShape)
+	}
+	if maxIter < 1 {
+		panic("mat: number of iterations must be positive")
+	}
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	dst.reuseAsZeroed(n, k)
+
+	// [S × A] = [(Aᵀ × Sᵀ)ᵀ] = s × n
+	var qr QR
+	if s := sketchLeastSquaresOversample * n; s < m {
+		var z Dense
+		newSRHT(m, s, rnd).sketch(&z, A.T(), nil)
+		qr.Factorize(z.T())
+	} else {
+		// The sketch would not be smaller than A,
+		// so R is computed from A itself.
+		qr.Factorize(A)
+	}
+	var rd Dense
+	qr.RTo(&rd)
+	r := blas64.Triangular{
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+		N:      n,
+		Stride: rd.mat.Stride,
+		Data:   rd.mat.Data,
+	}
+	var rmax float64
+	for i := 0; i < n; i++ {
+		rmax = math.Max(rmax, math.Abs(rd.at(i, i)))
+	}
+	const eps = 1.0 / (1 << 53)
+	for i := 0; i < n; i++ {
+		if !(math.Abs(rd.at(i, i)) > float64(n)*eps*rmax) {
+			return 0, 0, ErrSingular
+		}
+	}
+
+	u := NewVecDense(m, nil)
+	au := NewVecDense(m, nil)
+	v := NewVecDense(n, nil)
+	atv := NewVecDense(n, nil)
+	w := NewVecDense(n, nil)
+	y := NewVecDense(n, nil)
+	// mul places A R⁻¹ x into dst, and mulT places R⁻ᵀ Aᵀ x into dst.
+	mul := func(dst, x *VecDense) {
+		atv.CopyVec(x)
+		blas64.Trsv(blas.NoTrans, r, atv.mat)
+		dst.MulVec(A, atv)
+	}
+	mulT := func(dst, x *VecDense) {
+		dst.MulVec(A.T(), x)
+		blas64.Trsv(blas.Trans, r, dst.mat)
+	}
+
+	var ss float64
+	for col := 0; col < k; col++ {
+		for i := 0; i < m; i++ {
+			u.setVec(i, b.At(i, col))
+		}
+		y.Zero()
+		bnorm := Norm(u, 2)
+
+		// Golub–Kahan bidiagonalization of A R⁻¹ started
+		// from b, following Paige and Saunders, "LSQR: An
+		// algorithm for sparse linear equations and sparse
+		// least squares", ACM TOMS 8(1), 1982.
+		beta := bnorm
+		var alpha float64
+		if beta > 0 {
+			u.ScaleVec(1/beta, u)
+			mulT(v, u)
+			alpha = Norm(v, 2)
+		}
+		if alpha > 0 {
+			v.ScaleVec(1/alpha, v)
+		}
+		w.CopyVec(v)
+		phiBar, rhoBar := beta, alpha
+		anorm2 := alpha * alpha
+
+		var iter int
+		converged := alpha == 0 || beta == 0
+		for ; iter < maxIter && !converged; iter++ {
+			mul(au, v)
+			u.AddScaledVec(au, -alpha, u)
+			beta = Norm(u, 2)
+			if beta > 0 {
+				u.ScaleVec(1/beta, u)
+				mulT(atv, u)
+				v.AddScaledVec(atv, -beta, v)
+				alpha = Norm(v, 2)
+				if alpha > 0 {
+					v.ScaleVec(1/alpha, v)
+				}
+			} else {
+				alpha = 0
+			}
+			anorm2 += alpha*alpha + beta*beta
+
+			rho := math.Hypot(rhoBar, beta)
+			c, sn := rhoBar/rho, beta/rho
+			theta := sn * alpha
+			rhoBar = -c * alpha
+			phi := c * phiBar
+			phiBar *= sn
+
+			y.AddScaledVec(y, phi/rho, w)
+			w.AddScaledVec(v, -theta/rho, w)
+
+			// ‖r‖ = φ̄ and ‖(A R⁻¹)ᵀ r‖ = φ̄ α |c|.
+			converged = phiBar <= tol*bnorm ||
+				alpha*math.Abs(c) <= tol*math.Sqrt(anorm2)
+		}
+		if !converged {
+			err = ErrIterationLimit
+		}
+		iterations = max(iterations, iter)
+
+		// [x] = [R⁻¹ × y] = n × 1
+		blas64.Trsv(blas.NoTrans, r, y.mat)
+		au.MulVec(A, y)
+		for i := 0; i < m; i++ {
+			d := b.At(i, col) - au.at(i)
+			ss += d * d
+		}
+		for i := 0; i < n; i++ {
+			dst.set(i, col, y.at(i))
+		}
+	}
+	return math.Sqrt(ss), iterations, err
+}
@@ -0,0 +1,118 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSketchLeastSquares(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const k = 2
+
+	for _, test := range []struct {
+		m, n int
+		cond float64
+	}{
+		{m: 1, n: 1, cond: 1},
+		{m: 10, n: 10, cond: 1e3},
+		{m: 100, n: 5, cond: 1},
+		{m: 300, n: 20, cond: 1e8},
+		{m: 500, n: 30, cond: 1e10},
+	} {
+		sv := make([]float64, test.n)
+		for i := range sv {
+			sv[i] = math.Pow(test.cond, -float64(i)/math.Max(1, float64(test.n-1)))
+		}
+		a := NewTestMatrix(test.m, test.n, sv, rnd)
+		b := NewRandomNormalDense(test.m, k, rnd)
+
+		var want Dense
+		var qr QR
+		qr.Factorize(a)
+		if err := qr.SolveTo(&want, false, b); err != nil {
+			t.Fatalf("%d×%d: unexpected QR error: %v", test.m, test.n, err)
+		}
+		var wantRes Dense
+		wantRes.Mul(a, &want)
+		wantRes.Sub(b, &wantRes)
+
+		// The preconditioned iteration count does not
+		// depend on the condition of A.
+		const maxIter = 50
+		var got Dense
+		res, iter, err := SketchLeastSquares(&got, a, b, maxIter, 1e-14, rnd)
+		if err != nil {
+			t.Errorf("%d×%d: unexpected error: %v", test.m, test.n, err)
+		}
+		if iter < 1 || maxIter < iter {
+			t.Errorf("%d×%d: unexpected iteration count: %d", test.m, test.n, iter)
+		}
+		tol := 1e-12 * test.cond
+		var diff Dense
+		diff.Sub(&got, &want)
+		if Norm(&diff, 2) > tol*Norm(&want, 2) {
+			t.Errorf("%d×%d: unexpected least-squares solution: relative error %v", test.m, test.n, Norm(&diff, 2)/Norm(&want, 2))
+		}
+		if want := Norm(&wantRes, 2); math.Abs(res-want) > 1e-8*math.Max(want, 1) {
+			t.Errorf("%d×%d: unexpected residual: got:%v want:%v", test.m, test.n, res, want)
+		}
+	}
+
+	// A consistent system and a zero right-hand side.
+	a := NewRandomNormalDense(60, 8, rnd)
+	x := NewRandomNormalDense(8, 1, rnd)
+	b := NewDense(60, 2, nil)
+	var ax Dense
+	ax.Mul(a, x)
+	b.Slice(0, 60, 0, 1).(*Dense).Copy(&ax)
+	var got Dense
+	res, _, err := SketchLeastSquares(&got, a, b, 20, 1e-14, rnd)
+	if err != nil {
+		t.Errorf("unexpected error for consistent system: %v", err)
+	}
+	if res > 1e-10 {
+		t.Errorf("unexpected residual for consistent system: %v", res)
+	}
+	if !EqualApprox(got.Slice(0, 8, 0, 1), x, 1e-10) {
+		t.Error("unexpected solution of consistent system")
+	}
+	if !isZeroDense(DenseCopyOf(got.Slice(0, 8, 1, 2))) {
+		t.Error("unexpected non-zero solution for zero right-hand side")
+	}
+
+	// A rank deficient matrix is detected from the sketch.
+	var low Dense
+	low.Mul(NewRandomNormalDense(60, 3, rnd), NewRandomNormalDense(3, 8, rnd))
+	got.Reset()
+	_, _, err = SketchLeastSquares(&got, &low, b, 20, 1e-14, rnd)
+	if err != ErrSingular {
+		t.Errorf("unexpected error for rank deficient matrix: got:%v want:%v", err, ErrSingular)
+	}
+
+	got.Reset()
+	_, iter, err := SketchLeastSquares(&got, a, NewRandomNormalDense(60, 1, rnd), 1, 0, rnd)
+	if err != ErrIterationLimit || iter != 1 {
+		t.Errorf("unexpected result for iteration limit: iter:%d err:%v", iter, err)
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "wide", fn: func() { SketchLeastSquares(&Dense{}, NewDense(3, 4, nil), NewDense(3, 1, nil), 1, 0, rnd) }},
+		{name: "rows of b", fn: func() { SketchLeastSquares(&Dense{}, a, NewDense(59, 1, nil), 1, 0, rnd) }},
+		{name: "iterations", fn: func() { SketchLeastSquares(&Dense{}, a, b, 0, 0, rnd) }},
+		{name: "tolerance", fn: func() { SketchLeastSquares(&Dense{}, a, b, 1, -1, rnd) }},
+	} {
+		if p, _ := panics(test.fn); !p {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
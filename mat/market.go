@@ -0,0 +1,79 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteMatrixMarket writes the real matrix m to w in the Matrix Market
+// exchange format read by MATLAB, Octave and SciPy, returning any error
+// encountered while writing.
+//
+// If fewer than half of the elements of m are non-zero, m is written in the
+// coordinate format, listing the 1-based row and column indices and the value
+// of each non-zero element in column-major order. Otherwise m is written in
+// the dense array format, listing all elements in column-major order. If m
+// implements Symmetric, the symmetric variant of the format is written and
+// only the elements on and below the diagonal are listed. The values are
+// formatted with the smallest number of digits that represents them exactly.
+func WriteMatrixMarket(w io.Writer, m Matrix) error {
+	r, c := m.Dims()
+	_, sym := m.(Symmetric)
+	symmetry := "general"
+	if sym {
+		symmetry = "symmetric"
+	}
+	// first returns the first row of column j that is written.
+	first := func(j int) int {
+		if sym {
+			return j
+		}
+		return 0
+	}
+
+	var nnz, size int
+	for j := 0; j < c; j++ {
+		for i := first(j); i < r; i++ {
+			size++
+			if m.At(i, j) != 0 {
+				nnz++
+			}
+		}
+	}
+	coordinate := 2*nnz < size
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 64)
+	if coordinate {
+		fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real %s\n%d %d %d\n", symmetry, r, c, nnz)
+	} else {
+		fmt.Fprintf(bw, "%%%%MatrixMarket matrix array real %s\n%d %d\n", symmetry, r, c)
+	}
+	for j := 0; j < c; j++ {
+		for i := first(j); i < r; i++ {
+			v := m.At(i, j)
+			buf = buf[:0]
+			if coordinate {
+				if v == 0 {
+					continue
+				}
+				buf = strconv.AppendInt(buf, int64(i+1), 10)
+				buf = append(buf, ' ')
+				buf = strconv.AppendInt(buf, int64(j+1), 10)
+				buf = append(buf, ' ')
+			}
+			buf = strconv.AppendFloat(buf, v, 'g', -1, 64)
+			buf = append(buf, '\n')
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
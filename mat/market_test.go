@@ -0,0 +1,89 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteMatrixMarket(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		m    Matrix
+		want string
+	}{
+		{
+			name: "array",
+			m:    NewDense(2, 3, []float64{1, 2.5, 0, -4, 5e-20, 6}),
+			want: `%%MatrixMarket matrix array real general
+2 3
+1
+-4
+2.5
+5e-20
+0
+6
+`,
+		},
+		{
+			name: "coordinate",
+			m:    NewDense(3, 2, []float64{0, 1, 0, 0, 3, 0}),
+			want: `%%MatrixMarket matrix coordinate real general
+3 2 2
+3 1 3
+1 2 1
+`,
+		},
+		{
+			name: "symmetric array",
+			m:    NewSymDense(2, []float64{1, 2, 2, 3}),
+			want: `%%MatrixMarket matrix array real symmetric
+2 2
+1
+2
+3
+`,
+		},
+		{
+			name: "symmetric coordinate",
+			m:    NewSymDense(3, []float64{1, 0, 0, 0, 0, 7, 0, 7, 0}),
+			want: `%%MatrixMarket matrix coordinate real symmetric
+3 3 2
+1 1 1
+3 2 7
+`,
+		},
+		{
+			name: "transposed",
+			m:    NewDense(1, 2, []float64{1, 2}).T(),
+			want: `%%MatrixMarket matrix array real general
+2 1
+1
+2
+`,
+		},
+	} {
+		var buf bytes.Buffer
+		if err := WriteMatrixMarket(&buf, test.m); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s: unexpected output:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+
+	errWrite := errors.New("write failed")
+	err := WriteMatrixMarket(failWriter{errWrite}, NewDense(100, 100, nil))
+	if err != errWrite {
+		t.Errorf("unexpected error: got:%v want:%v", err, errWrite)
+	}
+}
+
+type failWriter struct{ err error }
+
+func (w failWriter) Write([]byte) (int, error) { return 0, w.err }
@@ -366,6 +366,108 @@ func Row(dst []float64, i int, a Matrix) []float64 {
 	return dst
 }
 
+// Diag copies the elements on the diagonal of the matrix into the slice dst.
+// The length of the provided slice must equal min(r,c) for an r×c matrix,
+// unless the slice is nil in which case a new slice is first allocated.
+func Diag(dst []float64, a Matrix) []float64 {
+	r, c := a.Dims()
+	n := min(r, c)
+	if dst == nil {
+		dst = make([]float64, n)
+	} else {
+		if len(dst) != n {
+			panic(ErrSliceLengthMismatch)
+		}
+	}
+	aU, _ := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok {
+		m := rm.RawMatrix()
+		blas64.Copy(blas64.Vector{N: n, Inc: m.Stride + 1, Data: m.Data},
+			blas64.Vector{N: n, Inc: 1, Data: dst},
+		)
+		return dst
+	}
+	for i := range dst {
+		dst[i] = a.At(i, i)
+	}
+	return dst
+}
+
+// ColNorms places the Euclidean norms of the columns of a into dst. The
+// length of dst must equal the number of columns of a. The norms are
+// computed in a single pass over a, and only the non-zero elements are
+// visited if a implements NonZeroDoer.
+func ColNorms(a Matrix, dst []float64) {
+	_, c := a.Dims()
+	if len(dst) != c {
+		panic(ErrColLength)
+	}
+	lineNorms(dst, a, true)
+}
+
+// RowNorms places the Euclidean norms of the rows of a into dst. The
+// length of dst must equal the number of rows of a. The norms are
+// computed in a single pass over a, and only the non-zero elements are
+// visited if a implements NonZeroDoer.
+func RowNorms(a Matrix, dst []float64) {
+	r, _ := a.Dims()
+	if len(dst) != r {
+		panic(ErrRowLength)
+	}
+	lineNorms(dst, a, false)
+}
+
+// lineNorms places the Euclidean norms of the columns of a into dst if
+// cols is true, and of the rows of a otherwise.
+func lineNorms(dst []float64, a Matrix, cols bool) {
+	zero(dst)
+	aU, aTrans := untranspose(a)
+	// Accumulate along the columns of aU if that
+	// corresponds to the lines requested of a.
+	cols = cols != aTrans
+	switch aU := aU.(type) {
+	case RawMatrixer:
+		m := aU.RawMatrix()
+		for i := 0; i < m.Rows; i++ {
+			row := m.Data[i*m.Stride : i*m.Stride+m.Cols]
+			if cols {
+				for j, v := range row {
+					dst[j] += v * v
+				}
+			} else {
+				var ss float64
+				for _, v := range row {
+					ss += v * v
+				}
+				dst[i] = ss
+			}
+		}
+	case NonZeroDoer:
+		aU.DoNonZero(func(i, j int, v float64) {
+			if cols {
+				dst[j] += v * v
+			} else {
+				dst[i] += v * v
+			}
+		})
+	default:
+		r, c := aU.Dims()
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				v := aU.At(i, j)
+				if cols {
+					dst[j] += v * v
+				} else {
+					dst[i] += v * v
+				}
+			}
+		}
+	}
+	for i, v := range dst {
+		dst[i] = math.Sqrt(v)
+	}
+}
+
 // Cond returns the condition number of the given matrix under the given norm.
 // The condition number must be based on the 1-norm, 2-norm or ∞-norm.
 // Cond will panic with matrix.ErrShape if the matrix has zero size.
@@ -585,6 +687,39 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 	return true
 }
 
+// FrobeniusEqualApprox returns whether the matrices a and b have the same size
+// and are equal with tolerance epsilon in the Frobenius norm, that is
+//  ‖a - b‖_F <= epsilon
+// or
+//  ‖a - b‖_F <= epsilon * max(‖a‖_F, ‖b‖_F).
+// Unlike EqualApprox, which requires every element to be within tolerance,
+// FrobeniusEqualApprox measures the error of the matrix as a whole, which is
+// the appropriate comparison for approximations such as low-rank
+// reconstructions whose individual small elements may have large relative
+// errors. Matrices with non-equal shapes are not equal.
+func FrobeniusEqualApprox(a, b Matrix, epsilon float64) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false
+	}
+	var diff, na, nb float64
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			va, vb := a.At(i, j), b.At(i, j)
+			d := va - vb
+			diff += d * d
+			na += va * va
+			nb += vb * vb
+		}
+	}
+	diff = math.Sqrt(diff)
+	if diff <= epsilon {
+		return true
+	}
+	return diff <= epsilon*math.Sqrt(math.Max(na, nb))
+}
+
 // LogDet returns the log of the determinant and the sign of the determinant
 // for the matrix that has been factorized. Numerical stability in product and
 // division expressions is generally improved by working in log space.
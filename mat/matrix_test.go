@@ -202,6 +202,104 @@ func TestRow(t *testing.T) {
 	testOneInputFunc(t, "Row", f, denseComparison, sameAnswerF64SliceOfSlice, isAnyType, isAnySize)
 }
 
+func TestDiag(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		a    Matrix
+		want []float64
+	}{
+		{a: NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}), want: []float64{1, 5, 9}},
+		{a: NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}), want: []float64{1, 5}},
+		{a: NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}).T(), want: []float64{1, 4}},
+		{a: NewDense(4, 4, []float64{
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+			9, 10, 11, 12,
+			13, 14, 15, 16,
+		}).Slice(1, 4, 1, 3), want: []float64{6, 11}},
+		{a: NewSymDense(2, []float64{3, 1, 1, 4}), want: []float64{3, 4}},
+	} {
+		if got := Diag(nil, test.a); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected diagonal of %T: got:%v want:%v", test.a, got, test.want)
+		}
+		got := make([]float64, len(test.want))
+		if Diag(got, test.a); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected diagonal filled for %T: got:%v want:%v", test.a, got, test.want)
+		}
+	}
+
+	denseComparison := func(a *Dense) interface{} {
+		r, c := a.Dims()
+		ans := make([]float64, min(r, c))
+		for i := range ans {
+			ans[i] = a.At(i, i)
+		}
+		return [][]float64{ans}
+	}
+	f := func(a Matrix) interface{} {
+		return [][]float64{Diag(nil, a)}
+	}
+	testOneInputFunc(t, "Diag", f, denseComparison, sameAnswerF64SliceOfSlice, isAnyType, isAnySize)
+
+	if p, _ := panics(func() { Diag(make([]float64, 2), NewDense(3, 3, nil)) }); !p {
+		t.Error("expected panic for mismatched slice length")
+	}
+}
+
+func TestColRowNorms(t *testing.T) {
+	t.Parallel()
+	a := NewDense(3, 4, []float64{
+		3, 0, 1, 0,
+		4, 0, 1, 2,
+		0, 5, 1, 0,
+	})
+	wantCol := []float64{5, 5, math.Sqrt(3), 2}
+	wantRow := []float64{math.Sqrt(10), math.Sqrt(21), math.Sqrt(26)}
+	for _, m := range []Matrix{a, asBasicMatrix(a), NewBandDense(3, 4, 2, 3, []float64{
+		0, 0, 3, 0, 1, 0,
+		0, 4, 0, 1, 2, 0,
+		0, 5, 1, 0, 0, 0,
+	})} {
+		col := make([]float64, 4)
+		ColNorms(m, col)
+		if !floats.EqualApprox(col, wantCol, 1e-14) {
+			t.Errorf("unexpected column norms for %T: got:%v want:%v", m, col, wantCol)
+		}
+		row := make([]float64, 3)
+		RowNorms(m, row)
+		if !floats.EqualApprox(row, wantRow, 1e-14) {
+			t.Errorf("unexpected row norms for %T: got:%v want:%v", m, row, wantRow)
+		}
+		ColNorms(m.T(), row)
+		if !floats.EqualApprox(row, wantRow, 1e-14) {
+			t.Errorf("unexpected column norms for transposed %T: got:%v want:%v", m, row, wantRow)
+		}
+		RowNorms(m.T(), col)
+		if !floats.EqualApprox(col, wantCol, 1e-14) {
+			t.Errorf("unexpected row norms for transposed %T: got:%v want:%v", m, col, wantCol)
+		}
+	}
+
+	denseComparison := func(a *Dense) interface{} {
+		_, c := a.Dims()
+		ans := make([]float64, c)
+		for j := range ans {
+			ans[j] = Norm(a.ColView(j), 2)
+		}
+		return [][]float64{ans}
+	}
+	f := func(a Matrix) interface{} {
+		_, c := a.Dims()
+		ans := make([]float64, c)
+		ColNorms(a, ans)
+		return [][]float64{ans}
+	}
+	sameAnswerApprox := func(a, b interface{}) bool {
+		return floats.EqualApprox(a.([][]float64)[0], b.([][]float64)[0], 1e-14)
+	}
+	testOneInputFunc(t, "ColNorms", f, denseComparison, sameAnswerApprox, isAnyType, isAnySize)
+}
+
 func TestCond(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -447,6 +545,46 @@ func TestEqual(t *testing.T) {
 	testTwoInputFunc(t, "Equal", f, denseComparison, sameAnswerBool, legalTypesAll, isAnySize2)
 }
 
+func TestFrobeniusEqualApprox(t *testing.T) {
+	t.Parallel()
+	a := NewDense(3, 2, []float64{
+		100, 1e-8,
+		-50, 20,
+		3, 0,
+	})
+	// Large relative errors in small elements
+	// are tolerated if the overall error is small.
+	b := DenseCopyOf(a)
+	b.Set(0, 1, 2e-8)
+	b.Set(2, 1, 1e-9)
+	if EqualApprox(a, b, 1e-9) {
+		t.Error("unexpected element-wise equality")
+	}
+	if !FrobeniusEqualApprox(a, b, 1e-9) {
+		t.Error("unexpected Frobenius inequality for small perturbation")
+	}
+	if !FrobeniusEqualApprox(b.T(), a.T(), 1e-9) {
+		t.Error("unexpected Frobenius inequality for transposed matrices")
+	}
+
+	c := DenseCopyOf(a)
+	c.Set(1, 1, 21)
+	if FrobeniusEqualApprox(a, c, 1e-6) {
+		t.Error("unexpected Frobenius equality for large perturbation")
+	}
+	if !FrobeniusEqualApprox(a, c, 1e-2) {
+		t.Error("unexpected Frobenius inequality within loose tolerance")
+	}
+
+	// Absolute tolerance applies near zero.
+	if !FrobeniusEqualApprox(NewDense(2, 2, nil), NewDense(2, 2, []float64{1e-12, 0, 0, 0}), 1e-10) {
+		t.Error("unexpected Frobenius inequality near zero")
+	}
+	if FrobeniusEqualApprox(a, a.T(), 1) {
+		t.Error("unexpected equality for mismatched shapes")
+	}
+}
+
 func TestMax(t *testing.T) {
 	t.Parallel()
 	// A direct test of Max with *Dense arguments is in TestNewDense.
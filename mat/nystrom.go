@@ -0,0 +1,291 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// nystromOversample is the number of columns sampled by Nystrom
+// in addition to the requested rank.
+const nystromOversample = 10
+
+// NystromSampling specifies how the columns of a Nyström approximation are
+// sampled.
+type NystromSampling int
+
+const (
+	// NystromUniform samples the columns uniformly at random
+	// without replacement.
+	NystromUniform NystromSampling = iota
+
+	// NystromLeverage samples the columns without replacement
+	// with probabilities proportional to approximate rank-k
+	// leverage scores, which are the squared row norms of an
+	// orthonormal basis of a Gaussian sketch A Ω. Computing
+	// the scores costs one product with A, and gives more
+	// accurate approximations than uniform sampling when the
+	// information in A is concentrated in few columns.
+	NystromLeverage
+)
+
+// Nystrom is a type for creating and using the Nyström approximation of a
+// symmetric positive semidefinite matrix,
+//  A ≈ C W⁺ Cᵀ = U Λ Uᵀ,
+// where C = A[:, J] holds l sampled columns of A, W = A[J, J] is the l×l
+// intersection of the sampled rows and columns, U is n×k with orthonormal
+// columns and Λ is the k×k diagonal matrix of approximate eigenvalues. Only
+// the sampled columns of A are used, so for kernel matrices the
+// approximation requires only n·l kernel evaluations.
+type Nystrom struct {
+	cols    []int
+	values  []float64
+	vectors *Dense
+
+	err error
+}
+
+// Factorize computes the rank-k Nyström approximation of the n×n symmetric
+// positive semidefinite matrix A from l = min(k+10, n) columns sampled as
+// specified by sampling, using rnd as the source of randomness. If rnd is nil,
+// the global rand source is used. The pseudo-inverse W⁺ is formed from the
+// eigendecomposition of W with the eigenvalues below l·ε times the largest
+// set to zero, and the approximation is truncated to its k largest
+// eigenvalues. The rank of the approximation is less than k if the numerical
+// rank of W is less than k.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails with ErrNotPSD if W has an eigenvalue below -l·ε times the largest
+// eigenvalue magnitude of W, with ErrSingular if W is zero and with
+// ErrFailedEigen or ErrFailedSVD if a dense decomposition fails. W is a
+// principal submatrix of A, so a negative eigenvalue of W shows that A is
+// indefinite, but an indefinite A is not detected when the sampled columns
+// miss its negative eigenvectors. The reason for a failure is returned by
+// Err. If the decomposition failed, routines that require a successful
+// factorization will panic.
+// Factorize will panic if k is not in [1, n] or if sampling is not a known
+// NystromSampling.
+func (ny *Nystrom) Factorize(A Symmetric, k int, sampling NystromSampling, rnd *rand.Rand) (ok bool) {
+	n := A.Symmetric()
+	if k < 1 || n < k {
+		panic(ErrShape)
+	}
+	if sampling != NystromUniform && sampling != NystromLeverage {
+		panic("mat: unknown Nyström sampling")
+	}
+	ny.cols, ny.values, ny.vectors = nil, nil, nil
+	ny.err = nil
+	l := min(k+nystromOversample, n)
+
+	var cols []int
+	switch sampling {
+	case NystromUniform:
+		perm := rand.Perm
+		if rnd != nil {
+			perm = rnd.Perm
+		}
+		cols = perm(n)[:l]
+	case NystromLeverage:
+		cols = leverageSample(A, k, l, rnd)
+	}
+	sort.Ints(cols)
+
+	// [C] = [A[:, J]] = n × l
+	c := NewDense(n, l, nil)
+	for i := 0; i < n; i++ {
+		row := c.rawRowView(i)
+		for j, cj := range cols {
+			row[j] = A.At(i, cj)
+		}
+	}
+
+	// [W] = [A[J, J]] = [V × Λ × Vᵀ] = l × l
+	w := NewSymDense(l, nil)
+	for i, ci := range cols {
+		for j := i; j < l; j++ {
+			w.SetSym(i, j, c.at(ci, j))
+		}
+	}
+	var eig EigenSym
+	if !eig.Factorize(w, true) {
+		ny.err = ErrFailedEigen
+		return false
+	}
+	lambda := eig.Values(nil)
+	var v Dense
+	eig.VectorsTo(&v)
+	const eps = 1.0 / (1 << 53)
+	tol := float64(l) * eps * math.Max(lambda[l-1], -lambda[0])
+	if lambda[0] < -tol {
+		ny.err = ErrNotPSD
+		return false
+	}
+	if !(tol > 0) {
+		ny.err = ErrSingular
+		return false
+	}
+
+	// [F] = [C × V × Λ^(-1/2)] = n × l
+	for j, e := range lambda {
+		f := 0.0
+		if e > tol {
+			f = 1 / math.Sqrt(e)
+		}
+		for i := 0; i < l; i++ {
+			v.set(i, j, v.at(i, j)*f)
+		}
+	}
+	var f Dense
+	f.Mul(c, &v)
+
+	// A ≈ F Fᵀ = U Σ² Uᵀ with F = U Σ Zᵀ.
+	var svd SVD
+	if !svd.Factorize(&f, SVDThinU) {
+		ny.err = ErrFailedSVD
+		return false
+	}
+	s := svd.Values(nil)
+	var u Dense
+	svd.UTo(&u)
+	r := 0
+	for r < k && s[r] > 0 {
+		r++
+	}
+	if r == 0 {
+		ny.err = ErrSingular
+		return false
+	}
+	ny.values = make([]float64, r)
+	for i := range ny.values {
+		ny.values[i] = s[i] * s[i]
+	}
+	ny.vectors = DenseCopyOf(u.Slice(0, n, 0, r))
+	ny.cols = cols
+	return true
+}
+
+// leverageSample returns l distinct indices sampled without replacement from
+// [0, n) with probabilities proportional to the approximate rank-k leverage
+// scores of the n×n matrix a.
+func leverageSample(a Matrix, k, l int, rnd *rand.Rand) []int {
+	_, n := a.Dims()
+	var y Dense
+	y.Mul(a, makeRandomMatrix(n, k, distNormal, rnd))
+	q := orthonormalBasis(&y)
+	return weightedSample(leverageScores(q), l, rnd)
+}
+
+// weightedSample returns l distinct indices sampled without replacement from
+// [0, len(weights)) with probabilities proportional to the non-negative
+// weights. The weights are overwritten.
+func weightedSample(weights []float64, l int, rnd *rand.Rand) []int {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	uniform := rand.Float64
+	if rnd != nil {
+		uniform = rnd.Float64
+	}
+
+	// Sequential sampling without replacement, removing the
+	// weight of each drawn index from the remaining total.
+	idx := make([]int, 0, l)
+	for len(idx) < l {
+		// Fall back to uniform weights for the remaining
+		// indices when the weights have been exhausted.
+		if !(total > 0) {
+			for i, w := range weights {
+				if w >= 0 {
+					weights[i] = 1
+					total++
+				}
+			}
+		}
+		target := uniform() * total
+		p := -1
+		for i, w := range weights {
+			if w < 0 {
+				continue
+			}
+			p = i
+			target -= w
+			if target < 0 {
+				break
+			}
+		}
+		total -= weights[p]
+		weights[p] = -1
+		idx = append(idx, p)
+	}
+	return idx
+}
+
+func (ny *Nystrom) succFact() bool {
+	return ny.vectors != nil
+}
+
+// Err returns the reason for a factorization failure.
+func (ny *Nystrom) Err() error {
+	return ny.err
+}
+
+// Rank returns the rank of the approximation.
+//
+// Rank will panic if the receiver does not contain a successful factorization.
+func (ny *Nystrom) Rank() int {
+	if !ny.succFact() {
+		panic(badFact)
+	}
+	return len(ny.values)
+}
+
+// Columns returns the indices of the sampled columns of A in increasing order.
+//
+// Columns will panic if the receiver does not contain a successful factorization.
+func (ny *Nystrom) Columns() []int {
+	if !ny.succFact() {
+		panic(badFact)
+	}
+	return append([]int(nil), ny.cols...)
+}
+
+// Values returns the approximate eigenvalues of A in descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length rank, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (ny *Nystrom) Values(dst []float64) []float64 {
+	if !ny.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(ny.values))
+	}
+	if len(dst) != len(ny.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, ny.values)
+	return dst
+}
+
+// UTo extracts the n×rank matrix U of approximate eigenvectors of A.
+//
+// If dst is empty, UTo will resize dst to be n×rank. When dst is non-empty,
+// UTo will panic if dst is not n×rank. UTo will also panic if the receiver
+// does not contain a successful factorization.
+func (ny *Nystrom) UTo(dst *Dense) {
+	if !ny.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(ny.vectors.Dims())
+	dst.Copy(ny.vectors)
+}
@@ -0,0 +1,87 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNystrom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 60
+	lambda := []float64{10, 5, 2, 1, 0.5, 1e-3, 1e-4}
+	a := randSymWithEigenvalues(n, lambda, rnd)
+	for _, sampling := range []NystromSampling{NystromUniform, NystromLeverage} {
+		for _, k := range []int{2, 5, 7} {
+			var ny Nystrom
+			if !ny.Factorize(a, k, sampling, rnd) {
+				t.Fatalf("sampling=%d k=%d: unexpected factorization failure", sampling, k)
+			}
+			if got := ny.Rank(); got != k {
+				t.Errorf("sampling=%d k=%d: unexpected rank %d", sampling, k, got)
+			}
+			cols := ny.Columns()
+			if len(cols) != min(k+nystromOversample, n) || !sort.IntsAreSorted(cols) || !isIndexSet(cols, n) {
+				t.Errorf("sampling=%d k=%d: invalid column set %v", sampling, k, cols)
+			}
+			vals := ny.Values(nil)
+			for i, v := range vals {
+				// The sampled columns span the range of A,
+				// so the values are eigenvalues of A.
+				if math.Abs(v-lambda[i]) > 1e-8 {
+					t.Errorf("sampling=%d k=%d: unexpected value %d: got:%v want:%v", sampling, k, i, v, lambda[i])
+				}
+			}
+			var u Dense
+			ny.UTo(&u)
+			if !hasOrthonormalColumns(&u, 1e-10) {
+				t.Errorf("sampling=%d k=%d: vectors not orthonormal", sampling, k)
+			}
+		}
+	}
+
+	// Leverage sampling finds the columns that carry the information.
+	sparse := NewSymDense(n, nil)
+	for i := 0; i < 3; i++ {
+		sparse.SetSym(3*i+1, 3*i+1, float64(3-i))
+	}
+	var ny Nystrom
+	if !ny.Factorize(sparse, 3, NystromLeverage, rnd) {
+		t.Fatal("unexpected factorization failure")
+	}
+	cols := ny.Columns()
+	for _, want := range []int{1, 4, 7} {
+		if i := sort.SearchInts(cols, want); i == len(cols) || cols[i] != want {
+			t.Errorf("column %d not sampled: %v", want, cols)
+		}
+	}
+
+	if ny.Factorize(NewSymDense(n, nil), 2, NystromUniform, rnd) {
+		t.Error("expected failure for zero matrix")
+	}
+	if err := ny.Err(); err != ErrSingular {
+		t.Errorf("unexpected error for zero matrix: got:%v want:%v", err, ErrSingular)
+	}
+	indef := randSymWithEigenvalues(n, []float64{3, 2, -1}, rnd)
+	for _, sampling := range []NystromSampling{NystromUniform, NystromLeverage} {
+		if ny.Factorize(indef, 2, sampling, rnd) {
+			t.Errorf("expected failure for indefinite matrix with sampling %d", sampling)
+		}
+		if err := ny.Err(); err != ErrNotPSD {
+			t.Errorf("unexpected error for indefinite matrix with sampling %d: got:%v want:%v", sampling, err, ErrNotPSD)
+		}
+	}
+	if p, _ := panics(func() { ny.Factorize(a, 0, NystromUniform, rnd) }); !p {
+		t.Error("expected panic for zero rank")
+	}
+	if p, _ := panics(func() { ny.Factorize(a, 2, NystromSampling(-1), rnd) }); !p {
+		t.Error("expected panic for unknown sampling")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+// ompTol is the relative residual norm below which
+// Orthogonal Matching Pursuit stops adding columns.
+const ompTol = 1e-12
+
+// OMP finds sparse solutions x to the underdetermined systems A x = y by
+// Orthogonal Matching Pursuit, storing them in the columns of dst. A is an
+// m×n matrix, typically with n > m, and each column of the m×k matrix y is
+// treated as a separate system, so that dst is n×k.
+//
+// For each column of y, OMP greedily selects at most sparsity columns of A.
+// At each step the column of A most correlated with the current residual,
+// relative to its norm, is added to the support. The QR factorization of the
+// columns of A on the support is extended by orthogonalizing the new column
+// against the current orthonormal basis by modified Gram–Schmidt with one
+// reorthogonalization, and the residual is updated by removing its component
+// along the new basis vector. A step with s columns in the support therefore
+// costs one product with Aᵀ and O(m·s) operations for the update, rather than
+// the O(m·s²) of a new factorization. The least-squares solution on the
+// support is computed from the triangular factor once the support is final.
+// The iteration stops early when the residual is zero to working precision.
+// The elements of dst outside the supports are zero.
+//
+// Recovery of the sparsest solution is only guaranteed under conditions on
+// A, such as A having independent random Gaussian elements and sufficiently
+// many rows relative to sparsity.
+//
+// If dst is empty, OMP will resize dst to be n×k. When dst is non-empty, OMP
+// will panic if dst is not n×k. OMP will panic if y does not have m rows or
+// if sparsity is not in [1, min(m,n)]. If a least-squares problem on a support
+// is near-singular, the solution is still computed and a Condition error is
+// returned. If a selected column lies in the span of the support, it is not
+// added, the iteration for that column of y stops and a Condition error is
+// returned.
+func OMP(dst *Dense, A Matrix, y Matrix, sparsity int) error {
+	m, n := A.Dims()
+	ym, k := y.Dims()
+	if ym != m {
+		panic(ErrShape)
+	}
+	if sparsity < 1 || min(m, n) < sparsity {
+		panic(ErrShape)
+	}
+	dst.reuseAsZeroed(n, k)
+
+	norms := make([]float64, n)
+	ColNorms(A, norms)
+
+	var err error
+	b := NewVecDense(m, nil)
+	r := NewVecDense(m, nil)
+	c := NewVecDense(n, nil)
+	v := NewVecDense(m, nil)
+	inSupport := make([]bool, n)
+	// The columns of A on the support are A[:, support] = Q R,
+	// with the leading columns of q holding Q and the leading
+	// block of rt holding R. qtb holds Qᵀ b.
+	q := NewDense(m, sparsity, nil)
+	rt := NewDense(sparsity, sparsity, nil)
+	qtb := make([]float64, sparsity)
+	work := getFloats(3*sparsity, false)
+	defer putFloats(work)
+	iwork := getInts(sparsity, false)
+	defer putInts(iwork)
+	for col := 0; col < k; col++ {
+		for i := 0; i < m; i++ {
+			b.setVec(i, y.At(i, col))
+		}
+		r.CopyVec(b)
+		tol := ompTol * Norm(b, 2)
+		for j := range inSupport {
+			inSupport[j] = false
+		}
+		rt.Zero()
+		var support []int
+		for len(support) < sparsity && Norm(r, 2) > tol {
+			// Select the column most correlated with the residual.
+			c.MulVec(A.T(), r)
+			best, bestVal := -1, 0.0
+			for j := 0; j < n; j++ {
+				if inSupport[j] || norms[j] == 0 {
+					continue
+				}
+				v := math.Abs(c.at(j)) / norms[j]
+				if v > bestVal {
+					best, bestVal = j, v
+				}
+			}
+			if best < 0 {
+				break
+			}
+
+			// Orthogonalize the new column against the basis,
+			// repeating the modified Gram–Schmidt pass once to
+			// keep the basis orthonormal to working precision.
+			s := len(support)
+			for i := 0; i < m; i++ {
+				v.setVec(i, A.At(i, best))
+			}
+			for pass := 0; pass < 2; pass++ {
+				for i := 0; i < s; i++ {
+					qi := q.ColView(i)
+					h := Dot(qi, v)
+					rt.set(i, s, rt.at(i, s)+h)
+					v.AddScaledVec(v, -h, qi)
+				}
+			}
+			norm := Norm(v, 2)
+			if norm == 0 {
+				// The column is in the span of the support.
+				if err == nil {
+					err = Condition(math.Inf(1))
+				}
+				break
+			}
+			rt.set(s, s, norm)
+			qs := q.ColView(s).(*VecDense)
+			qs.ScaleVec(1/norm, v)
+			support = append(support, best)
+			inSupport[best] = true
+
+			// The residual is orthogonal to the previous basis
+			// vectors, so only its component along the new one
+			// is removed.
+			h := Dot(qs, r)
+			qtb[s] = h
+			r.AddScaledVec(r, -h, qs)
+		}
+		s := len(support)
+		if s == 0 {
+			continue
+		}
+
+		// Solve the least-squares problem on the support:
+		// [x] = [R⁻¹ × Qᵀ b] = s × 1
+		tr := blas64.Triangular{
+			Uplo:   blas.Upper,
+			Diag:   blas.NonUnit,
+			N:      s,
+			Stride: rt.mat.Stride,
+			Data:   rt.mat.Data,
+		}
+		x := blas64.Vector{N: s, Inc: 1, Data: qtb[:s]}
+		blas64.Trsv(blas.NoTrans, tr, x)
+		if cond := 1 / lapack64.Trcon(CondNorm, tr, work, iwork); cond > ConditionTolerance && err == nil {
+			err = Condition(cond)
+		}
+		for j, sj := range support {
+			dst.set(sj, col, x.Data[j])
+		}
+	}
+	return err
+}
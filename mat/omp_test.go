@@ -0,0 +1,103 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestOMP(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k, sparsity, nonzero int
+	}{
+		{m: 40, n: 100, k: 1, sparsity: 5, nonzero: 5},
+		{m: 40, n: 100, k: 3, sparsity: 8, nonzero: 4},
+		{m: 60, n: 200, k: 2, sparsity: 10, nonzero: 10},
+	} {
+		a := NewRandomNormalDense(test.m, test.n, rnd)
+		want := NewDense(test.n, test.k, nil)
+		for j := 0; j < test.k; j++ {
+			for _, i := range rnd.Perm(test.n)[:test.nonzero] {
+				want.set(i, j, rnd.NormFloat64())
+			}
+		}
+		var y Dense
+		y.Mul(a, want)
+
+		var got Dense
+		err := OMP(&got, a, &y, test.sparsity)
+		if err != nil {
+			t.Errorf("m=%d n=%d: unexpected error: %v", test.m, test.n, err)
+			continue
+		}
+		if !EqualApprox(&got, want, 1e-10) {
+			t.Errorf("m=%d n=%d: sparse solution not recovered", test.m, test.n)
+		}
+	}
+
+	// A zero right-hand side has a zero solution.
+	a := NewRandomNormalDense(10, 20, rnd)
+	var got Dense
+	err := OMP(&got, a, NewDense(10, 1, nil), 3)
+	if err != nil {
+		t.Fatalf("unexpected error for zero right-hand side: %v", err)
+	}
+	if !Equal(&got, NewDense(20, 1, nil)) {
+		t.Error("unexpected non-zero solution for zero right-hand side")
+	}
+
+	for _, sparsity := range []int{0, 11} {
+		if p, _ := panics(func() { OMP(&Dense{}, a, NewDense(10, 1, nil), sparsity) }); !p {
+			t.Errorf("expected panic for sparsity %d", sparsity)
+		}
+	}
+	if p, _ := panics(func() { OMP(&Dense{}, a, NewDense(9, 1, nil), 3) }); !p {
+		t.Error("expected panic for mismatched right-hand side")
+	}
+}
+
+func TestOMPLeastSquares(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// A dense right-hand side is fitted by least squares
+	// on the selected support.
+	const m, n, sparsity = 30, 50, 6
+	a := NewRandomNormalDense(m, n, rnd)
+	y := NewRandomNormalDense(m, 1, rnd)
+	var got Dense
+	if err := OMP(&got, a, y, sparsity); err != nil {
+		t.Fatalf("unexpected error for dense right-hand side: %v", err)
+	}
+	var support []int
+	for i := 0; i < n; i++ {
+		if got.At(i, 0) != 0 {
+			support = append(support, i)
+		}
+	}
+	if len(support) != sparsity {
+		t.Fatalf("unexpected support size for dense right-hand side: got:%d want:%d", len(support), sparsity)
+	}
+	sub := NewDense(m, sparsity, nil)
+	for j, sj := range support {
+		for i := 0; i < m; i++ {
+			sub.Set(i, j, a.At(i, sj))
+		}
+	}
+	var want Dense
+	if err := want.Solve(sub, y); err != nil {
+		t.Fatalf("unexpected error solving least squares on the support: %v", err)
+	}
+	for j, sj := range support {
+		if math.Abs(got.At(sj, 0)-want.At(j, 0)) > 1e-12 {
+			t.Errorf("unexpected least-squares coefficient %d: got:%v want:%v", sj, got.At(sj, 0), want.At(j, 0))
+		}
+	}
+}
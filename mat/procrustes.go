@@ -0,0 +1,64 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// Procrustes solves the orthogonal Procrustes problem, finding the orthogonal
+// matrix R that minimizes
+//  ‖a R - b‖_F
+// for the k×n point sets a and b, and placing R into dst. The solution is
+//  R = U Vᵀ
+// where U Σ Vᵀ is the singular value decomposition of aᵀ b.
+//
+// If reflect is false, R is restricted to proper rotations with det(R) = 1,
+// otherwise R may include a reflection. Procrustes also returns the scale
+// factor s minimizing ‖s a R - b‖_F for the returned R.
+//
+// If dst is empty, Procrustes will resize dst to be n×n. When dst is non-empty,
+// Procrustes will panic if dst is not n×n. Procrustes will panic if a and b
+// do not have the same shape. If the singular value decomposition fails,
+// Procrustes returns ErrFailedSVD.
+func Procrustes(dst *Dense, a, b Matrix, reflect bool) (scale float64, err error) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(ac, ac)
+
+	var m Dense
+	m.Mul(a.T(), b)
+	var svd SVD
+	if !svd.Factorize(&m, SVDThin) {
+		return 0, ErrFailedSVD
+	}
+	var u, v Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	s := svd.Values(nil)
+
+	if !reflect {
+		dst.Mul(&u, v.T())
+		if Det(dst) < 0 {
+			// Flip the direction associated with the smallest
+			// singular value to turn the reflection into a rotation.
+			n := len(s) - 1
+			for i := 0; i < ac; i++ {
+				u.set(i, n, -u.at(i, n))
+			}
+			s[n] = -s[n]
+		}
+	}
+	dst.Mul(&u, v.T())
+
+	var tr float64
+	for _, v := range s {
+		tr += v
+	}
+	na := Norm(a, 2)
+	if na == 0 {
+		return 0, nil
+	}
+	return tr / (na * na), nil
+}
@@ -0,0 +1,99 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestProcrustes(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		k, n    int
+		scale   float64
+		reflect bool
+	}{
+		{k: 10, n: 2, scale: 1, reflect: false},
+		{k: 20, n: 3, scale: 2.5, reflect: false},
+		{k: 20, n: 3, scale: 0.5, reflect: true},
+		{k: 50, n: 6, scale: 1, reflect: true},
+	} {
+		a := NewDense(test.k, test.n, nil)
+		for i := range a.mat.Data {
+			a.mat.Data[i] = rnd.NormFloat64()
+		}
+
+		// Construct a random orthogonal matrix with
+		// the required determinant sign.
+		g := NewDense(test.n, test.n, nil)
+		for i := range g.mat.Data {
+			g.mat.Data[i] = rnd.NormFloat64()
+		}
+		var qr QR
+		qr.Factorize(g)
+		var want Dense
+		qr.QTo(&want)
+		if det := Det(&want); (det < 0) != test.reflect {
+			for i := 0; i < test.n; i++ {
+				want.Set(i, 0, -want.At(i, 0))
+			}
+		}
+
+		var b Dense
+		b.Mul(a, &want)
+		b.Scale(test.scale, &b)
+
+		var r Dense
+		s, err := Procrustes(&r, a, &b, test.reflect)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !EqualApprox(&r, &want, 1e-10) {
+			t.Errorf("unexpected rotation for k=%d n=%d:\ngot:\n%v\nwant:\n%v",
+				test.k, test.n, Formatted(&r), Formatted(&want))
+		}
+		if math.Abs(s-test.scale) > 1e-10 {
+			t.Errorf("unexpected scale for k=%d n=%d: got:%v want:%v", test.k, test.n, s, test.scale)
+		}
+	}
+}
+
+func TestProcrustesNoReflect(t *testing.T) {
+	t.Parallel()
+	// b is a reflection of a, so the best rotation
+	// must differ from the reflection.
+	a := NewDense(4, 2, []float64{
+		1, 0,
+		0, 1,
+		-1, 0,
+		2, 3,
+	})
+	b := NewDense(4, 2, []float64{
+		-1, 0,
+		0, 1,
+		1, 0,
+		-2, 3,
+	})
+	var r Dense
+	_, err := Procrustes(&r, a, b, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if det := Det(&r); math.Abs(det-1) > 1e-12 {
+		t.Errorf("unexpected determinant of rotation: got:%v want:1", det)
+	}
+	_, err = Procrustes(&r, a, b, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := NewDense(2, 2, []float64{-1, 0, 0, 1})
+	if !EqualApprox(&r, want, 1e-12) {
+		t.Errorf("unexpected reflection:\ngot:\n%v\nwant:\n%v", Formatted(&r), Formatted(want))
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// QB is a type for creating and using the randomized QB decomposition of a
+// matrix,
+//  A ≈ Q B,
+// where Q is m×k with orthonormal columns spanning an approximate range of A
+// and B = Qᵀ A is k×n. The QB decomposition is the intermediate of the
+// randomized singular value decomposition computed by RSVD, and is useful on
+// its own as a compressed representation of A and as the input to further
+// factorizations of the small matrix B.
+type QB struct {
+	q, b *Dense
+}
+
+// Factorize computes the QB decomposition of the m×n matrix A with a basis of
+// rank columns. Q is an orthonormal basis of the sketch (A Aᵀ)^p A Ω for a
+// random n×rank test matrix Ω, where p is the number of power iterations,
+// and B is computed exactly as Qᵀ A.
+//
+// The options RSVDSource, RSVDSketch, RSVDOrthoProjection and RSVDPowerIter
+// have the same effect on the sketch as for RSVD.Factorize. With
+// RSVDTolerance, the basis is instead grown adaptively by a RangeFinder until
+// ‖A - Q B‖_F is at most the tolerance, and rank is an upper bound of the
+// number of columns of Q. Other options are ignored.
+//
+// If A is zero, Q is formed from the leading columns of the identity and B is
+// zero. Factorize will panic if rank is not in [1, min(m,n)].
+func (qb *QB) Factorize(A Matrix, rank int, opts ...RSVDOption) {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	m, n := A.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic(ErrShape)
+	}
+
+	var q *Dense
+	if cfg.adaptive {
+		var rf RangeFinder
+		rf.Find(A, rank, opts...)
+		q = rf.q
+	} else {
+		// [Z] = [A × Ω] = m × rank
+		z := NewDense(m, rank, nil)
+		if cfg.sketch == SketchSRHT && !cfg.orthoProjection {
+			newSRHT(n, rank, cfg.rnd).sketch(z, A, nil)
+		} else {
+			sketchMul(z, A, cfg.projection(n, rank))
+		}
+		if isZeroDense(z) {
+			q = NewDense(m, rank, nil)
+			for i := 0; i < rank; i++ {
+				q.set(i, i, 1)
+			}
+		} else {
+			if cfg.powerIter > 0 {
+				powerIterate(z, A, cfg.powerIter, nil, nil)
+			}
+			q = orthonormalBasis(z)
+		}
+	}
+
+	// [B] = [Qᵀ × A] = k × n
+	_, k := q.Dims()
+	b := NewDense(k, n, nil)
+	projectMul(b, q, A)
+	qb.q, qb.b = q, b
+}
+
+func (qb *QB) succFact() bool {
+	return qb.q != nil
+}
+
+// Dims returns the dimensions of the factorized matrix A.
+//
+// Dims will panic if the receiver does not contain a factorization.
+func (qb *QB) Dims() (m, n int) {
+	if !qb.succFact() {
+		panic(badFact)
+	}
+	m, _ = qb.q.Dims()
+	_, n = qb.b.Dims()
+	return m, n
+}
+
+// Rank returns the number of columns k of Q.
+//
+// Rank will panic if the receiver does not contain a factorization.
+func (qb *QB) Rank() int {
+	if !qb.succFact() {
+		panic(badFact)
+	}
+	_, k := qb.q.Dims()
+	return k
+}
+
+// QTo extracts the m×k orthonormal factor Q.
+//
+// If dst is empty, QTo will resize dst to be m×k. When dst is non-empty, QTo
+// will panic if dst is not m×k. QTo will also panic if the receiver does not
+// contain a factorization.
+func (qb *QB) QTo(dst *Dense) {
+	if !qb.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(qb.q.Dims())
+	dst.Copy(qb.q)
+}
+
+// BTo extracts the k×n factor B = Qᵀ A.
+//
+// If dst is empty, BTo will resize dst to be k×n. When dst is non-empty, BTo
+// will panic if dst is not k×n. BTo will also panic if the receiver does not
+// contain a factorization.
+func (qb *QB) BTo(dst *Dense) {
+	if !qb.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(qb.b.Dims())
+	dst.Copy(qb.b)
+}
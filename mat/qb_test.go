@@ -0,0 +1,71 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestQB(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{10, 5, 3, 1, 1e-3, 1e-4, 1e-5}
+	for _, test := range []struct {
+		m, n, rank int
+		opts       []RSVDOption
+	}{
+		{m: 30, n: 20, rank: 4},
+		{m: 20, n: 30, rank: 4, opts: []RSVDOption{RSVDPowerIter(2)}},
+		{m: 30, n: 20, rank: 4, opts: []RSVDOption{RSVDSketch(SketchSRHT)}},
+		{m: 30, n: 20, rank: 4, opts: []RSVDOption{RSVDOrthoProjection()}},
+		{m: 30, n: 20, rank: 10, opts: []RSVDOption{RSVDTolerance(1e-2), RSVDAdaptiveBlock(2)}},
+	} {
+		a := NewTestMatrix(test.m, test.n, s, rnd)
+		var qb QB
+		qb.Factorize(a, test.rank, append(test.opts, withRand(rnd))...)
+		k := qb.Rank()
+		if k < 1 || test.rank < k {
+			t.Errorf("%d×%d: unexpected rank %d", test.m, test.n, k)
+		}
+		if r, c := qb.Dims(); r != test.m || c != test.n {
+			t.Errorf("%d×%d: unexpected dimensions %d×%d", test.m, test.n, r, c)
+		}
+		var q, b, got Dense
+		qb.QTo(&q)
+		qb.BTo(&b)
+		if !hasOrthonormalColumns(&q, 1e-12) {
+			t.Errorf("%d×%d: Q not orthonormal", test.m, test.n)
+		}
+		var want Dense
+		want.Mul(q.T(), a)
+		if !EqualApprox(&b, &want, 1e-12) {
+			t.Errorf("%d×%d: B is not Qᵀ A", test.m, test.n)
+		}
+		got.Mul(&q, &b)
+		got.Sub(a, &got)
+		if e, best := Norm(&got, 2), BestRankKError(a, k); e > 1e-2 && e > 10*best {
+			t.Errorf("%d×%d: approximation error %v too large, best %v", test.m, test.n, e, best)
+		}
+	}
+
+	var qb QB
+	qb.Factorize(NewDense(5, 4, nil), 2, withRand(rnd))
+	var b Dense
+	qb.BTo(&b)
+	if qb.Rank() != 2 || Norm(&b, 2) != 0 {
+		t.Error("unexpected factorization of zero matrix")
+	}
+	a := NewRandomNormalDense(5, 4, rnd)
+	for _, rank := range []int{0, 5} {
+		if p, _ := panics(func() { qb.Factorize(a, rank) }); !p {
+			t.Errorf("expected panic for rank %d", rank)
+		}
+	}
+	if p, _ := panics(func() { (&QB{}).QTo(&Dense{}) }); !p {
+		t.Error("expected panic for empty receiver")
+	}
+}
@@ -159,6 +159,30 @@ func (qr *QR) QTo(dst *Dense) {
 	putFloats(work)
 }
 
+// thinQTo extracts the first n columns of the orthonormal matrix Q of the QR
+// decomposition of an m×n matrix into dst, which is resized to be m×n. Unlike
+// QTo, the m×m matrix Q is not formed.
+func (qr *QR) thinQTo(dst *Dense) {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+
+	r, c := qr.qr.Dims()
+	dst.reuseAsZeroed(r, c)
+
+	// Set Q to the first c columns of I.
+	for i := 0; i < c; i++ {
+		dst.mat.Data[i*dst.mat.Stride+i] = 1
+	}
+
+	// Construct Q from the elementary reflectors.
+	work := []float64{0}
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, len(work))
+	putFloats(work)
+}
+
 // SolveTo finds a minimum-norm solution to a system of linear equations defined
 // by the matrices A and b, where A is an m×n matrix represented in its QR factorized
 // form. If A is singular or near-singular a Condition error is returned.
@@ -73,6 +73,32 @@ func isOrthonormal(q *Dense, tol float64) bool {
 	return true
 }
 
+func TestQRThinQ(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{m: 5, n: 5},
+		{m: 10, n: 5},
+		{m: 100, n: 3},
+		{m: 7, n: 1},
+	} {
+		a := NewRandomNormalDense(test.m, test.n, rnd)
+		var qr QR
+		qr.Factorize(a)
+		var full, thin Dense
+		qr.QTo(&full)
+		qr.thinQTo(&thin)
+		if r, c := thin.Dims(); r != test.m || c != test.n {
+			t.Errorf("unexpected thin Q size for %d×%d: %d×%d", test.m, test.n, r, c)
+		}
+		if !EqualApprox(&thin, full.Slice(0, test.m, 0, test.n), 1e-14) {
+			t.Errorf("thin Q mismatch for %d×%d", test.m, test.n)
+		}
+	}
+}
+
 func TestQRSolveTo(t *testing.T) {
 	t.Parallel()
 	rnd := rand.New(rand.NewSource(1))
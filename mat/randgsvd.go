@@ -0,0 +1,230 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// RandGSVD is a type for creating and using a randomized rank-rank generalized
+// singular value decomposition of the r×c matrix A and the p×c matrix B,
+//  A ≈ U C X,
+//  B ≈ V S X,
+// where U is r×rank and V is p×rank with orthonormal columns, C and S are
+// rank×rank diagonal matrices with non-negative elements satisfying
+// C² + S² = I, and X is the rank×c transformation shared by A and B. The
+// ratios of the diagonal elements of C and S are the generalized singular
+// values of the pair, and they measure the relative importance of each shared
+// direction X[i,:] in A and in B.
+//
+// A and B must be column-conformant, that is they must have the same number
+// of columns, which hold the same variables observed in the two data sets.
+// The rows of A and of B are independent observations and their number may
+// differ.
+type RandGSVD struct {
+	r, p, c, rank int
+
+	u, v, x *Dense
+	ca, cb  []float64
+}
+
+// Factorize computes the randomized generalized singular value decomposition
+// of A and B. A randomized singular value decomposition of the stacked
+// (r+p)×c matrix
+//  [ A ] ≈ [ W_A ] Σ Zᵀ
+//  [ B ]   [ W_B ]
+// with the parameters chosen by RSVDAuto and rnd as the source of randomness
+// approximates the common row space of A and B. Since W = [ W_A; W_B ] has
+// orthonormal columns, W_Aᵀ W_A + W_Bᵀ W_B = I, so the singular value
+// decomposition W_A = U C Yᵀ gives the orthogonal columns W_B Y = V S, and
+// the shared transformation is X = Yᵀ Σ Zᵀ. If rnd is nil, the global rand
+// source is used.
+//
+// A direction that is absent from B has a zero element in S, and the
+// corresponding column of V is zero.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+// Factorize will panic if A and B do not have the same number of columns, or
+// if rank is not in [1, min(r,c)].
+func (gsvd *RandGSVD) Factorize(A, B Matrix, rank int, rnd *rand.Rand) (ok bool) {
+	r, c := A.Dims()
+	p, cb := B.Dims()
+	if c != cb {
+		panic(ErrShape)
+	}
+	if rank < 1 || min(r, c) < rank {
+		panic(ErrShape)
+	}
+	gsvd.rank = 0
+
+	var stacked Dense
+	stacked.Stack(A, B)
+	var rsvd RSVD
+	if !rsvd.Factorize(&stacked, rank, withRand(rnd), RSVDAuto()) {
+		return false
+	}
+	var w, z Dense
+	rsvd.UTo(&w)
+	rsvd.VTo(&z)
+	sigma := rsvd.Values(nil)
+	wa := w.Slice(0, r, 0, rank)
+	wb := w.Slice(r, r+p, 0, rank)
+
+	// [W_A] = [U × C × Yᵀ] = (r × rank) × (rank × rank) × (rank × rank)
+	var svd SVD
+	if !svd.Factorize(wa, SVDThin) {
+		return false
+	}
+	u, y := &Dense{}, &Dense{}
+	svd.UTo(u)
+	svd.VTo(y)
+	ca := svd.Values(nil)
+
+	// [V × S] = [W_B × Y] = p × rank, with the norms of the columns
+	// computed directly rather than as √(1 - c²), which would lose
+	// the accuracy of the small elements of S.
+	v := &Dense{}
+	v.Mul(wb, y)
+	sb := make([]float64, rank)
+	for j := range sb {
+		col := v.ColView(j).(*VecDense)
+		sb[j] = Norm(col, 2)
+		if sb[j] != 0 {
+			col.ScaleVec(1/sb[j], col)
+		}
+	}
+	for i := range ca {
+		// Rounding may give cosines slightly above one.
+		ca[i] = math.Min(ca[i], 1)
+	}
+
+	// [X] = [Yᵀ × Σ × Zᵀ] = rank × c
+	for j, s := range sigma {
+		row := y.rawRowView(j)
+		for i := range row {
+			row[i] *= s
+		}
+	}
+	x := &Dense{}
+	x.Mul(y.T(), z.T())
+
+	gsvd.r, gsvd.p, gsvd.c, gsvd.rank = r, p, c, rank
+	gsvd.u, gsvd.v, gsvd.x = u, v, x
+	gsvd.ca, gsvd.cb = ca, sb
+	return true
+}
+
+func (gsvd *RandGSVD) succFact() bool {
+	return gsvd.rank != 0
+}
+
+// Rank returns the rank of the factorization.
+//
+// Rank will panic if the receiver does not contain a successful factorization.
+func (gsvd *RandGSVD) Rank() int {
+	if !gsvd.succFact() {
+		panic(badFact)
+	}
+	return gsvd.rank
+}
+
+// ValuesA returns the diagonal elements of C, the singular values of A
+// relative to the shared transformation, in descending order.
+// If the input slice is non-nil, the values will be stored in-place into the
+// slice. In this case, the slice must have length rank, and ValuesA will panic
+// with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// ValuesA will panic if the receiver does not contain a successful factorization.
+func (gsvd *RandGSVD) ValuesA(s []float64) []float64 {
+	return gsvd.values(s, gsvd.ca)
+}
+
+// ValuesB returns the diagonal elements of S, the singular values of B
+// relative to the shared transformation, in ascending order.
+// If the input slice is non-nil, the values will be stored in-place into the
+// slice. In this case, the slice must have length rank, and ValuesB will panic
+// with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// ValuesB will panic if the receiver does not contain a successful factorization.
+func (gsvd *RandGSVD) ValuesB(s []float64) []float64 {
+	return gsvd.values(s, gsvd.cb)
+}
+
+// GeneralizedValues returns the generalized singular values C[i,i]/S[i,i] in
+// descending order, which are infinite for the directions absent from B.
+// If the input slice is non-nil, the values will be stored in-place into the
+// slice. In this case, the slice must have length rank, and GeneralizedValues
+// will panic with ErrSliceLengthMismatch otherwise. If the input slice is nil,
+// a new slice of the appropriate length will be allocated and returned.
+//
+// GeneralizedValues will panic if the receiver does not contain a successful factorization.
+func (gsvd *RandGSVD) GeneralizedValues(v []float64) []float64 {
+	v = gsvd.values(v, gsvd.ca)
+	for i, s := range gsvd.cb {
+		v[i] /= s
+	}
+	return v
+}
+
+func (gsvd *RandGSVD) values(dst, src []float64) []float64 {
+	if !gsvd.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, gsvd.rank)
+	}
+	if len(dst) != gsvd.rank {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, src)
+	return dst
+}
+
+// UTo extracts the r×rank matrix U from the decomposition, storing the result
+// into dst.
+//
+// If dst is empty, UTo will resize dst to be r×rank. When dst is non-empty,
+// UTo will panic if dst is not r×rank. UTo will also panic if the receiver
+// does not contain a successful factorization.
+func (gsvd *RandGSVD) UTo(dst *Dense) {
+	if !gsvd.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(gsvd.r, gsvd.rank)
+	dst.Copy(gsvd.u)
+}
+
+// VTo extracts the p×rank matrix V from the decomposition, storing the result
+// into dst.
+//
+// If dst is empty, VTo will resize dst to be p×rank. When dst is non-empty,
+// VTo will panic if dst is not p×rank. VTo will also panic if the receiver
+// does not contain a successful factorization.
+func (gsvd *RandGSVD) VTo(dst *Dense) {
+	if !gsvd.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(gsvd.p, gsvd.rank)
+	dst.Copy(gsvd.v)
+}
+
+// XTo extracts the rank×c shared transformation X from the decomposition,
+// storing the result into dst.
+//
+// If dst is empty, XTo will resize dst to be rank×c. When dst is non-empty,
+// XTo will panic if dst is not rank×c. XTo will also panic if the receiver
+// does not contain a successful factorization.
+func (gsvd *RandGSVD) XTo(dst *Dense) {
+	if !gsvd.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(gsvd.rank, gsvd.c)
+	dst.Copy(gsvd.x)
+}
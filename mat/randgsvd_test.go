@@ -0,0 +1,92 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestRandGSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, p, c, rank, stackRank int
+	}{
+		// A full-rank decomposition matches the deterministic GSVD.
+		{r: 12, p: 9, c: 5, rank: 5, stackRank: 5},
+		// A stacked matrix of low rank is recovered exactly.
+		{r: 20, p: 15, c: 30, rank: 4, stackRank: 4},
+	} {
+		x := NewRandomNormalDense(test.stackRank, test.c, rnd)
+		a := &Dense{}
+		a.Mul(NewRandomNormalDense(test.r, test.stackRank, rnd), x)
+		b := &Dense{}
+		b.Mul(NewRandomNormalDense(test.p, test.stackRank, rnd), x)
+
+		var gsvd RandGSVD
+		if !gsvd.Factorize(a, b, test.rank, rnd) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if gsvd.Rank() != test.rank {
+			t.Errorf("unexpected rank: got:%d want:%d", gsvd.Rank(), test.rank)
+		}
+		ca := gsvd.ValuesA(nil)
+		sb := gsvd.ValuesB(nil)
+		for i := range ca {
+			if math.Abs(ca[i]*ca[i]+sb[i]*sb[i]-1) > 1e-12 {
+				t.Errorf("%d×%d: values %d not normalized: c=%v s=%v", test.r, test.c, i, ca[i], sb[i])
+			}
+		}
+
+		var u, v, xf Dense
+		gsvd.UTo(&u)
+		gsvd.VTo(&v)
+		gsvd.XTo(&xf)
+		if !hasOrthonormalColumns(&u, 1e-12) || !hasOrthonormalColumns(&v, 1e-12) {
+			t.Errorf("%d×%d: singular vectors not orthonormal", test.r, test.c)
+		}
+		for _, m := range []struct {
+			name   string
+			want   *Dense
+			left   *Dense
+			values []float64
+		}{
+			{name: "A", want: a, left: &u, values: ca},
+			{name: "B", want: b, left: &v, values: sb},
+		} {
+			var got Dense
+			got.Product(m.left, NewDiagDense(test.rank, m.values), &xf)
+			if !EqualApprox(&got, m.want, 1e-10) {
+				t.Errorf("%d×%d: unexpected reconstruction of %s", test.r, test.c, m.name)
+			}
+		}
+
+		if test.rank == test.c {
+			var want GSVD
+			if !want.Factorize(a, b, GSVDNone) {
+				t.Fatal("unexpected GSVD failure")
+			}
+			wantValues := want.GeneralizedValues(nil)
+			sort.Sort(sort.Reverse(sort.Float64Slice(wantValues)))
+			if got := gsvd.GeneralizedValues(nil); !floats.EqualApprox(got, wantValues, 1e-10) {
+				t.Errorf("unexpected generalized values: got:%v want:%v", got, wantValues)
+			}
+		}
+	}
+
+	var gsvd RandGSVD
+	if p, _ := panics(func() { gsvd.Factorize(NewDense(4, 3, nil), NewDense(4, 2, nil), 1, nil) }); !p {
+		t.Error("expected panic for non-conformant matrices")
+	}
+	if p, _ := panics(func() { gsvd.Rank() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "golang.org/x/exp/rand"
+
+// randomDist is the distribution of the elements of a random matrix.
+type randomDist int
+
+const (
+	// distUniform is the uniform distribution on [0, 1).
+	distUniform randomDist = iota
+	// distNormal is the standard normal distribution.
+	distNormal
+	// distRademacher is the uniform distribution on {-1, 1}.
+	distRademacher
+)
+
+// NewRandomDense returns an r×c matrix with elements drawn independently
+// from the uniform distribution on [0, 1) using rnd. If rnd is nil, the
+// global rand source is used. Using a seeded rnd makes the returned matrix
+// reproducible, for example for benchmark fixtures.
+func NewRandomDense(r, c int, rnd *rand.Rand) *Dense {
+	return makeRandomMatrix(r, c, distUniform, rnd)
+}
+
+// NewRandomNormalDense returns an r×c matrix with elements drawn
+// independently from the standard normal distribution using rnd. If rnd
+// is nil, the global rand source is used.
+func NewRandomNormalDense(r, c int, rnd *rand.Rand) *Dense {
+	return makeRandomMatrix(r, c, distNormal, rnd)
+}
+
+// NewTestMatrix returns the m×n matrix
+//  U diag(singularValues) Vᵀ
+// where U and V have len(singularValues) orthonormal columns drawn from the
+// Haar distribution using rnd, so that the non-zero singular values of the
+// returned matrix are exactly the given values up to rounding. Such matrices
+// with a known spectrum are useful for validating the accuracy of randomized
+// decompositions. If rnd is nil, the global rand source is used.
+//
+// NewTestMatrix will panic if len(singularValues) is zero or greater than
+// min(m,n), or if any of the singular values is negative.
+func NewTestMatrix(m, n int, singularValues []float64, rnd *rand.Rand) *Dense {
+	k := len(singularValues)
+	if k == 0 || min(m, n) < k {
+		panic(ErrShape)
+	}
+	for _, s := range singularValues {
+		if s < 0 {
+			panic("mat: negative singular value")
+		}
+	}
+	u := haarOrthonormal(m, k, rnd)
+	v := haarOrthonormal(n, k, rnd)
+	u.ScaleCols(singularValues, u)
+	var a Dense
+	a.Mul(u, v.T())
+	return &a
+}
+
+// haarOrthonormal returns a rows×cols matrix with orthonormal columns drawn
+// from the Haar distribution. The columns of the orthogonal factor of the QR
+// factorization of a Gaussian matrix are Haar distributed once their signs
+// are fixed to make the diagonal of the triangular factor positive.
+func haarOrthonormal(rows, cols int, rnd *rand.Rand) *Dense {
+	var qr QR
+	qr.Factorize(makeRandomMatrix(rows, cols, distNormal, rnd))
+	var q, r Dense
+	qr.thinQTo(&q)
+	qr.RTo(&r)
+	d := make([]float64, cols)
+	for j := range d {
+		d[j] = 1
+		if r.at(j, j) < 0 {
+			d[j] = -1
+		}
+	}
+	q.ScaleCols(d, &q)
+	return &q
+}
+
+// DeriveSource returns a source of random numbers for the given worker
+// derived from the base seed. Sources derived from the same base seed for
+// different workers are seeded with distinct seeds derived by SplitMix64 from
+// the base seed and the worker index, so concurrent goroutines can each use
+// their own source while the whole computation remains reproducible from the
+// single base seed. No separation of the streams within the period of the
+// generator is guaranteed. For example, each worker of a pool can compute its
+// decomposition with
+//  rsvd.Factorize(a, rank, mat.RSVDSource(mat.DeriveSource(seed, worker)))
+// DeriveSource will panic if worker is negative.
+func DeriveSource(base int64, worker int) rand.Source {
+	if worker < 0 {
+		panic("mat: negative worker index")
+	}
+	// The seed is the worker'th output of a SplitMix64
+	// generator seeded with the mixed base seed. SplitMix64
+	// is a bijection, so distinct workers get distinct seeds.
+	return rand.NewSource(splitMix64(splitMix64(uint64(base)) + uint64(worker)*splitMixGamma))
+}
+
+// splitMixGamma is the increment of the SplitMix64 generator.
+const splitMixGamma = 0x9e3779b97f4a7c15
+
+// splitMix64 returns the SplitMix64 output for the state x.
+func splitMix64(x uint64) uint64 {
+	z := x + splitMixGamma
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// makeRandomMatrix creates random matrix with given amount of rows and cols
+// with elements drawn from dist using rnd, or the global rand source if rnd
+// is nil.
+func makeRandomMatrix(rows, columns int, dist randomDist, rnd *rand.Rand) *Dense {
+	if rows <= 0 || columns <= 0 {
+		if rows == 0 || columns == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	var sample func() float64
+	switch dist {
+	case distUniform:
+		sample = rand.Float64
+		if rnd != nil {
+			sample = rnd.Float64
+		}
+	case distNormal:
+		sample = rand.NormFloat64
+		if rnd != nil {
+			sample = rnd.NormFloat64
+		}
+	case distRademacher:
+		sample = rademacher(rnd)
+	default:
+		panic("mat: unknown random distribution")
+	}
+
+	data := make([]float64, rows*columns)
+	for i := range data {
+		data[i] = sample()
+	}
+	return NewDense(rows, columns, data)
+}
+
+// rademacher returns a function drawing samples from the uniform distribution
+// on {-1, 1} using rnd, or the global rand source if rnd is nil.
+func rademacher(rnd *rand.Rand) func() float64 {
+	uint64 := rand.Uint64
+	if rnd != nil {
+		uint64 = rnd.Uint64
+	}
+	return func() float64 {
+		return float64(int(uint64()&1)*2 - 1)
+	}
+}
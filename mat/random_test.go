@@ -0,0 +1,138 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestNewRandomDense(t *testing.T) {
+	t.Parallel()
+	const r, c = 200, 100
+	for _, test := range []struct {
+		name      string
+		fn        func(r, c int, rnd *rand.Rand) *Dense
+		mean, std float64
+		min, max  float64
+	}{
+		{name: "uniform", fn: NewRandomDense, mean: 0.5, std: 1 / math.Sqrt(12), min: 0, max: 1},
+		{name: "normal", fn: NewRandomNormalDense, mean: 0, std: 1, min: math.Inf(-1), max: math.Inf(1)},
+	} {
+		a := test.fn(r, c, rand.New(rand.NewSource(1)))
+		b := test.fn(r, c, rand.New(rand.NewSource(1)))
+		if !Equal(a, b) {
+			t.Errorf("%s: matrix not reproducible with equal seeds", test.name)
+		}
+		if gr, gc := a.Dims(); gr != r || gc != c {
+			t.Errorf("%s: unexpected dimensions: got:%d×%d want:%d×%d", test.name, gr, gc, r, c)
+		}
+
+		var sum, sumSq float64
+		for _, v := range a.mat.Data {
+			if v < test.min || test.max <= v {
+				t.Errorf("%s: element out of range: %v", test.name, v)
+			}
+			sum += v
+			sumSq += v * v
+		}
+		n := float64(len(a.mat.Data))
+		mean := sum / n
+		std := math.Sqrt(sumSq/n - mean*mean)
+		if math.Abs(mean-test.mean) > 5*test.std/math.Sqrt(n) {
+			t.Errorf("%s: unexpected mean: got:%v want:%v", test.name, mean, test.mean)
+		}
+		if math.Abs(std-test.std) > 0.02 {
+			t.Errorf("%s: unexpected standard deviation: got:%v want:%v", test.name, std, test.std)
+		}
+	}
+}
+
+func TestNewTestMatrix(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+		s    []float64
+	}{
+		{m: 10, n: 6, s: []float64{5, 3, 1}},
+		{m: 6, n: 10, s: []float64{4, 2, 2, 1e-3, 0, 0}},
+		{m: 7, n: 7, s: []float64{1}},
+	} {
+		a := NewTestMatrix(test.m, test.n, test.s, rnd)
+		if r, c := a.Dims(); r != test.m || c != test.n {
+			t.Errorf("unexpected size: got %d×%d, want %d×%d", r, c, test.m, test.n)
+			continue
+		}
+		var svd SVD
+		if !svd.Factorize(a, SVDNone) {
+			t.Fatal("unexpected SVD failure")
+		}
+		got := svd.Values(nil)
+		want := make([]float64, len(got))
+		copy(want, test.s)
+		if !floats.EqualApprox(got, want, 1e-12) {
+			t.Errorf("unexpected singular values: got %v, want %v", got, want)
+		}
+	}
+
+	for _, s := range [][]float64{nil, {1, 2, 3, 4, 5, 6, 7}, {1, -1}} {
+		if p, _ := panics(func() { NewTestMatrix(6, 7, s, rnd) }); !p {
+			t.Errorf("expected panic for singular values %v", s)
+		}
+	}
+}
+
+func TestDeriveSource(t *testing.T) {
+	t.Parallel()
+	const base, workers, n = 42, 8, 1000
+
+	streams := make([][]float64, workers)
+	for w := range streams {
+		rnd := rand.New(DeriveSource(base, w))
+		again := rand.New(DeriveSource(base, w))
+		streams[w] = make([]float64, n)
+		for i := range streams[w] {
+			streams[w][i] = rnd.NormFloat64()
+			if v := again.NormFloat64(); v != streams[w][i] {
+				t.Fatalf("worker %d stream not reproducible", w)
+			}
+		}
+	}
+
+	// Streams of different workers must be uncorrelated.
+	for i := 0; i < workers; i++ {
+		for j := i + 1; j < workers; j++ {
+			var c float64
+			for k := 0; k < n; k++ {
+				c += streams[i][k] * streams[j][k]
+			}
+			c /= n
+			if math.Abs(c) > 5/math.Sqrt(n) {
+				t.Errorf("correlated streams for workers %d and %d: %v", i, j, c)
+			}
+		}
+	}
+	if rand.New(DeriveSource(base, 0)).Uint64() == rand.New(DeriveSource(base+1, 0)).Uint64() {
+		t.Error("equal streams for different base seeds")
+	}
+
+	// Decompositions using equal derived sources are identical.
+	a := NewRandomNormalDense(30, 20, rand.New(rand.NewSource(1)))
+	var first, second RSVD
+	first.Factorize(a, 4, RSVDSource(DeriveSource(base, 3)))
+	second.Factorize(a, 4, RSVDSource(DeriveSource(base, 3)))
+	if !floats.Equal(first.Values(nil), second.Values(nil)) {
+		t.Error("decomposition not reproducible with derived source")
+	}
+
+	if p, _ := panics(func() { DeriveSource(base, -1) }); !p {
+		t.Error("expected panic for negative worker")
+	}
+}
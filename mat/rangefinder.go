@@ -0,0 +1,110 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// RangeFinder is a type for computing an orthonormal basis Q of the
+// approximate range of a matrix A, so that
+//  A ≈ Q Qᵀ A.
+// It is the first stage of the randomized singular value decomposition
+// computed by RSVD, and is the building block of other randomized methods
+// such as eigendecompositions, Nyström approximations and interpolative
+// decompositions, which need the basis rather than a factorization.
+type RangeFinder struct {
+	q     *Dense
+	resid float64
+	m, n  int
+}
+
+// Find computes an m×k orthonormal basis Q of the approximate range of the
+// m×n matrix A with k at most maxRank. The basis is grown from Gaussian
+// sketches A Ω in blocks of columns, each of which is orthogonalized against
+// the current basis, while the range error
+//  ‖A - Q Qᵀ A‖_F
+// is tracked exactly from the energy of A captured by Q, following the
+// adaptive randomized range finder of Halko, Martinsson and Tropp. The
+// growth stops when the range error is at most the tolerance or when the
+// basis has maxRank columns, so k is a multiple of the block size or
+// maxRank.
+//
+// The behavior of Find is controlled by the options RSVDSource, which sets
+// the source of randomness, RSVDAdaptiveBlock, which sets the block size with
+// a default of 10, and RSVDTolerance, which sets the absolute tolerance with
+// a default of zero, so that without it the basis has maxRank columns unless
+// the range of A is captured exactly. Other options are ignored. Since the
+// error is tracked by subtraction, tolerances below about √ε·‖A‖_F cannot be
+// resolved.
+//
+// If A is zero, the basis is the first column of the identity. Find will
+// panic if maxRank is not in [1, min(m,n)].
+func (rf *RangeFinder) Find(A Matrix, maxRank int, opts ...RSVDOption) {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	m, n := A.Dims()
+	if maxRank < 1 || min(m, n) < maxRank {
+		panic(ErrShape)
+	}
+	block := cfg.adaptiveBlock
+	if block == 0 {
+		block = rsvdAdaptiveBlock
+	}
+	q, resid := adaptiveRangeFinder(A, cfg.tol, block, maxRank, cfg.rnd)
+	if q == nil {
+		q = NewDense(m, 1, nil)
+		q.set(0, 0, 1)
+	}
+	rf.q, rf.resid = q, resid
+	rf.m, rf.n = m, n
+}
+
+func (rf *RangeFinder) succFact() bool {
+	return rf.q != nil
+}
+
+// Dims returns the dimensions of the matrix A whose range was found.
+//
+// Dims will panic if the receiver does not contain a computed basis.
+func (rf *RangeFinder) Dims() (m, n int) {
+	if !rf.succFact() {
+		panic(badFact)
+	}
+	return rf.m, rf.n
+}
+
+// Rank returns the number of columns k of the basis.
+//
+// Rank will panic if the receiver does not contain a computed basis.
+func (rf *RangeFinder) Rank() int {
+	if !rf.succFact() {
+		panic(badFact)
+	}
+	_, k := rf.q.Dims()
+	return k
+}
+
+// Residual returns the range error ‖A - Q Qᵀ A‖_F of the basis, as tracked
+// during Find.
+//
+// Residual will panic if the receiver does not contain a computed basis.
+func (rf *RangeFinder) Residual() float64 {
+	if !rf.succFact() {
+		panic(badFact)
+	}
+	return rf.resid
+}
+
+// QTo extracts the m×k orthonormal basis Q.
+//
+// If dst is empty, QTo will resize dst to be m×k. When dst is non-empty, QTo
+// will panic if dst is not m×k. QTo will also panic if the receiver does not
+// contain a computed basis.
+func (rf *RangeFinder) QTo(dst *Dense) {
+	if !rf.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(rf.q.Dims())
+	dst.Copy(rf.q)
+}
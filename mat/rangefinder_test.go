@@ -0,0 +1,90 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestRangeFinder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 50, 40
+	s := make([]float64, 30)
+	for i := range s {
+		s[i] = math.Pow(2, -float64(i)/2)
+	}
+	a := NewTestMatrix(m, n, s, rnd)
+	for _, test := range []struct {
+		maxRank int
+		block   int
+		tol     float64
+		want    int // Expected rank if tol is zero.
+	}{
+		{maxRank: 7, block: rsvdAdaptiveBlock, want: 7},
+		{maxRank: 7, block: 3, want: 7},
+		{maxRank: 40, block: 2, tol: 0.05},
+		{maxRank: 40, block: 1, tol: 0.05},
+	} {
+		opts := []RSVDOption{withRand(rnd), RSVDAdaptiveBlock(test.block)}
+		if test.tol != 0 {
+			opts = append(opts, RSVDTolerance(test.tol))
+		}
+		var rf RangeFinder
+		rf.Find(a, test.maxRank, opts...)
+		k := rf.Rank()
+		if test.tol == 0 && k != test.want {
+			t.Errorf("maxRank=%d: unexpected rank: got:%d want:%d", test.maxRank, k, test.want)
+		}
+		var q Dense
+		rf.QTo(&q)
+		if r, c := q.Dims(); r != m || c != k {
+			t.Errorf("maxRank=%d: unexpected basis size %d×%d", test.maxRank, r, c)
+		}
+		if !hasOrthonormalColumns(&q, 1e-12) {
+			t.Errorf("maxRank=%d: basis not orthonormal", test.maxRank)
+		}
+		var qta, res Dense
+		qta.Mul(q.T(), a)
+		res.Mul(&q, &qta)
+		res.Sub(a, &res)
+		got := Norm(&res, 2)
+		if math.Abs(got-rf.Residual()) > 1e-6 {
+			t.Errorf("maxRank=%d: unexpected residual: got:%v want:%v", test.maxRank, rf.Residual(), got)
+		}
+		if test.tol != 0 {
+			if got > test.tol {
+				t.Errorf("maxRank=%d: range error %v exceeds tolerance", test.maxRank, got)
+			}
+			// The basis may exceed the smallest sufficient rank
+			// by the block size and a few randomized directions.
+			best := 1
+			for BestRankKError(a, best) > test.tol {
+				best++
+			}
+			if k > best+test.block+2 {
+				t.Errorf("maxRank=%d: rank %d larger than needed %d", test.maxRank, k, best)
+			}
+		}
+	}
+
+	var rf RangeFinder
+	rf.Find(NewDense(m, n, nil), 5, withRand(rnd))
+	if rf.Rank() != 1 || rf.Residual() != 0 {
+		t.Errorf("unexpected basis for zero matrix: rank %d residual %v", rf.Rank(), rf.Residual())
+	}
+	for _, maxRank := range []int{0, n + 1} {
+		if p, _ := panics(func() { rf.Find(a, maxRank) }); !p {
+			t.Errorf("expected panic for maxRank %d", maxRank)
+		}
+	}
+	var empty RangeFinder
+	if p, _ := panics(func() { empty.Rank() }); !p {
+		t.Error("expected panic for empty receiver")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// REVD is a type for creating and using the randomized eigendecomposition of
+// a symmetric matrix,
+//  A ≈ U Λ Uᵀ,
+// where U is n×rank with orthonormal columns and Λ is the rank×rank diagonal
+// matrix of the eigenvalues of largest magnitude. The decomposition is
+// computed from an orthonormal basis Q of the approximate range of A and the
+// dense eigendecomposition of the small matrix Qᵀ A Q, so it is much cheaper
+// than EigenSym for large matrices of low numerical rank, such as kernel and
+// covariance matrices.
+type REVD struct {
+	values  []float64
+	vectors *Dense
+
+	err error
+}
+
+// Factorize computes the randomized eigendecomposition of the n×n symmetric
+// matrix A with the rank eigenvalues of largest magnitude. The range basis Q
+// is computed from the sketch A Ω as for RSVD.Factorize, and A is then read a
+// second time to form Qᵀ A Q.
+//
+// The options RSVDSource, RSVDOversample, RSVDPowerIter, RSVDSketch,
+// RSVDOrthoProjection, RSVDPivotedSketch and RSVDColumnBlock have the same
+// effect as for RSVD.Factorize; the other options are ignored. If rank is n,
+// the exact eigendecomposition is computed.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails with ErrFailedEigen if the dense eigendecomposition fails. If the
+// decomposition failed, routines that require a successful factorization will
+// panic. Factorize will panic if rank is not in [1, n].
+func (e *REVD) Factorize(A Symmetric, rank int, opts ...RSVDOption) (ok bool) {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	n := A.Symmetric()
+	if rank < 1 || n < rank {
+		panic(ErrShape)
+	}
+	e.values, e.vectors = nil, nil
+	e.err = nil
+
+	// Only the options of the symmetric path of RSVD apply.
+	cfg.standardize = false
+	cfg.skipU, cfg.skipV = false, false
+	var rsvd RSVD
+	if !rsvd.factorize(A, rank, cfg) {
+		e.err = ErrFailedEigen
+		return false
+	}
+	vectors := &Dense{}
+	rsvd.UTo(vectors)
+	e.values = rsvd.Eigenvalues(nil)
+	e.vectors = vectors
+	return true
+}
+
+// FactorizePSD computes the randomized eigendecomposition of the n×n symmetric
+// positive semidefinite matrix A with the rank largest eigenvalues, reading A
+// only once through the sketch Y = A Ω. A is approximated by the stabilized
+// Nyström approximation
+//  (Y + νΩ) (Ωᵀ(Y + νΩ))⁻¹ (Y + νΩ)ᵀ - νI,
+// where Ω is a random n×l matrix with orthonormal columns, l is rank plus the
+// oversampling set by RSVDOversample, and ν is a small shift that keeps the
+// inner matrix numerically positive definite, following Tropp, Yurtsever,
+// Udell and Cevher, "Fixed-rank approximation of a positive-semidefinite
+// matrix from streaming data", NeurIPS 2017. The eigenvalues are in
+// descending order and are non-negative. The options RSVDSource and
+// RSVDOversample are used; the other options are ignored.
+//
+// The approximation is more accurate than that of Factorize for the same
+// number of products with A when A is positive semidefinite, but it is not
+// meaningful otherwise.
+//
+// FactorizePSD returns whether the decomposition succeeded. The decomposition
+// fails with ErrNotPSD if the shifted inner matrix is not positive definite,
+// which indicates that A is not positive semidefinite, and with ErrFailedSVD if
+// the singular value decomposition fails. An indefinite A is not detected when
+// the sketch misses its negative eigenvectors. The reason for a failure is
+// returned by Err. FactorizePSD will panic if rank is not in [1, n].
+func (e *REVD) FactorizePSD(A Symmetric, rank int, opts ...RSVDOption) (ok bool) {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	n := A.Symmetric()
+	if rank < 1 || n < rank {
+		panic(ErrShape)
+	}
+	e.values, e.vectors = nil, nil
+	e.err = nil
+	l := min(rank+cfg.oversample, n)
+
+	// [Y] = [A × Ω] = n × l
+	omega := orthonormalRandomMatrix(n, l, cfg.rnd)
+	y := NewDense(n, l, nil)
+	sketchMul(y, A, omega)
+	if isZeroDense(y) {
+		e.values = make([]float64, rank)
+		e.vectors = NewDense(n, rank, nil)
+		for i := 0; i < rank; i++ {
+			e.vectors.set(i, i, 1)
+		}
+		return true
+	}
+
+	// [Yν] = [Y + ν Ω] = n × l
+	const eps = 1.0 / (1 << 53)
+	nu := math.Sqrt(float64(n)) * eps * Norm(y, 2)
+	for i := 0; i < n; i++ {
+		row := y.rawRowView(i)
+		for j, v := range omega.rawRowView(i) {
+			row[j] += nu * v
+		}
+	}
+
+	// [Ωᵀ × Yν] = Rᵀ R = l × l
+	var c Dense
+	c.Mul(omega.T(), y)
+	inner := NewSymDense(l, nil)
+	for i := 0; i < l; i++ {
+		for j := i; j < l; j++ {
+			inner.SetSym(i, j, 0.5*(c.at(i, j)+c.at(j, i)))
+		}
+	}
+	var chol Cholesky
+	if !chol.Factorize(inner) {
+		// The inner matrix is positive definite in exact
+		// arithmetic for a positive semidefinite A.
+		e.err = ErrNotPSD
+		return false
+	}
+	var r TriDense
+	chol.UTo(&r)
+
+	// [B] = [Yν × R⁻¹] = n × l
+	blas64.Trsm(blas.Right, blas.NoTrans, 1, r.mat, y.mat)
+
+	var svd SVD
+	if !svd.Factorize(y, SVDThinU) {
+		e.err = ErrFailedSVD
+		return false
+	}
+	s := svd.Values(nil)
+	var u Dense
+	svd.UTo(&u)
+
+	// The singular values are in descending order,
+	// so the leading rank columns are kept.
+	e.values = make([]float64, rank)
+	for i := range e.values {
+		e.values[i] = math.Max(0, s[i]*s[i]-nu)
+	}
+	e.vectors = DenseCopyOf(u.Slice(0, n, 0, rank))
+	return true
+}
+
+func (e *REVD) succFact() bool {
+	return e.vectors != nil
+}
+
+// Err returns the reason for a factorization failure.
+func (e *REVD) Err() error {
+	return e.err
+}
+
+// Rank returns the number of eigenpairs of the decomposition.
+//
+// Rank will panic if the receiver does not contain a successful factorization.
+func (e *REVD) Rank() int {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	return len(e.values)
+}
+
+// Values extracts the eigenvalues of the decomposition, ordered by decreasing
+// magnitude. If dst is non-nil, the values are stored in-place into dst. In
+// this case dst must have length rank, otherwise Values will panic. If dst is
+// nil, then a new slice will be allocated of the proper length and filled
+// with the eigenvalues.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (e *REVD) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the eigenvectors of the decomposition into the columns of
+// dst, in the order of the eigenvalues returned by Values.
+//
+// If dst is empty, VectorsTo will resize dst to be n×rank. When dst is
+// non-empty, VectorsTo will panic if dst is not n×rank. VectorsTo will also
+// panic if the receiver does not contain a successful factorization.
+func (e *REVD) VectorsTo(dst *Dense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(e.vectors.Dims())
+	dst.Copy(e.vectors)
+}
@@ -0,0 +1,110 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// randSymWithEigenvalues returns an n×n symmetric matrix with the
+// eigenvalues in lambda and zero, and random orthonormal eigenvectors.
+func randSymWithEigenvalues(n int, lambda []float64, rnd *rand.Rand) *SymDense {
+	w := orthonormalRandomMatrix(n, len(lambda), rnd)
+	a := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			var v float64
+			for k, l := range lambda {
+				v += w.at(i, k) * l * w.at(j, k)
+			}
+			a.SetSym(i, j, v)
+		}
+	}
+	return a
+}
+
+func TestREVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 40
+	for _, test := range []struct {
+		lambda []float64
+		rank   int
+		psd    bool
+	}{
+		{lambda: []float64{10, -6, 3, -1, 0.1, -0.01}, rank: 3},
+		{lambda: []float64{10, 6, 3, 1, 0.1, 0.01}, rank: 4, psd: true},
+		{lambda: []float64{5, 4, 3, 2, 1}, rank: 5, psd: true},
+	} {
+		a := randSymWithEigenvalues(n, test.lambda, rnd)
+		want := test.lambda[:test.rank]
+		factorizations := []func(*REVD) bool{
+			func(e *REVD) bool { return e.Factorize(a, test.rank, withRand(rnd), RSVDOversample(5)) },
+		}
+		if test.psd {
+			factorizations = append(factorizations,
+				func(e *REVD) bool { return e.FactorizePSD(a, test.rank, withRand(rnd), RSVDOversample(5)) })
+		}
+		for i, factorize := range factorizations {
+			var e REVD
+			if !factorize(&e) {
+				t.Fatalf("case %d: unexpected factorization failure", i)
+			}
+			if got := e.Values(nil); !floats.EqualApprox(got, want, 1e-8) {
+				t.Errorf("case %d: unexpected eigenvalues: got:%v want:%v", i, got, want)
+			}
+			var u Dense
+			e.VectorsTo(&u)
+			if r, c := u.Dims(); r != n || c != test.rank {
+				t.Errorf("case %d: unexpected vectors size %d×%d", i, r, c)
+			}
+			if !hasOrthonormalColumns(&u, 1e-10) {
+				t.Errorf("case %d: eigenvectors not orthonormal", i)
+			}
+			// A u_i = λ_i u_i.
+			var au Dense
+			au.Mul(a, &u)
+			for j, l := range e.Values(nil) {
+				for k := 0; k < n; k++ {
+					if math.Abs(au.at(k, j)-l*u.at(k, j)) > 1e-6 {
+						t.Errorf("case %d: eigenpair %d not satisfied", i, j)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	var e REVD
+	if !e.FactorizePSD(NewSymDense(5, nil), 2, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure for zero matrix")
+	}
+	if got := e.Values(nil); !floats.Equal(got, []float64{0, 0}) {
+		t.Errorf("unexpected eigenvalues of zero matrix: %v", got)
+	}
+	indef := randSymWithEigenvalues(10, []float64{3, -5}, rnd)
+	if e.FactorizePSD(indef, 2, withRand(rnd)) {
+		t.Error("expected failure for indefinite matrix")
+	}
+	if err := e.Err(); err != ErrNotPSD {
+		t.Errorf("unexpected error for indefinite matrix: got:%v want:%v", err, ErrNotPSD)
+	}
+	if !e.Factorize(indef, 2, withRand(rnd)) {
+		t.Error("unexpected failure of Factorize for indefinite matrix")
+	}
+	if err := e.Err(); err != nil {
+		t.Errorf("unexpected error after successful factorization: %v", err)
+	}
+	for _, rank := range []int{0, 11} {
+		if p, _ := panics(func() { e.Factorize(indef, rank) }); !p {
+			t.Errorf("expected panic for rank %d", rank)
+		}
+	}
+}
@@ -5,8 +5,54 @@
 package mat
 
 import (
+	"encoding/binary"
 	"fmt"
-	"math/rand"
+	"hash/fnv"
+	"log"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	noU = "mat: u not computed during factorization"
+	noV = "mat: v not computed during factorization"
+
+	badPerm = "mat: invalid permutation"
+)
+
+// LowRankFactorization is a low-rank factorization of an m×n matrix A,
+//  A ≈ U Σ Vᵀ,
+// where U is m×rank and V is n×rank, and Σ is the rank×rank diagonal matrix of
+// singular values. Algorithms that only use the factors, such as
+// reconstructing, projecting onto or solving with the approximation, can be
+// written against LowRankFactorization and used with any factorization
+// strategy.
+type LowRankFactorization interface {
+	// Dims returns the dimensions of the factorized matrix.
+	Dims() (m, n int)
+
+	// Rank returns the number of singular values and vectors.
+	Rank() int
+
+	// Values returns the singular values in descending order,
+	// with the semantics of SVD.Values.
+	Values(s []float64) []float64
+
+	// UTo and VTo extract the matrices U and V,
+	// with the semantics of SVD.UTo and SVD.VTo.
+	UTo(dst *Dense)
+	VTo(dst *Dense)
+}
+
+var (
+	_ LowRankFactorization = (*RSVD)(nil)
+	_ LowRankFactorization = (*TruncatedSVD)(nil)
 )
 
 // RSVD is a type for creating and using the Randomized Singular Value Decomposition (RSVD)
@@ -15,59 +61,1733 @@ type RSVD struct {
 	svd  *SVD
 	rank int
 	q    *Dense
-	m    int
+	m, n int
+
+	// scale holds the column scales applied
+	// to A when standardization was requested.
+	scale []float64
+
+	// eig holds the signed eigenvalues of a
+	// symmetric input in the order of the
+	// singular values.
+	eig []float64
+
+	stats RSVDStats
+
+	// randomized is whether the factors were
+	// computed from a randomized sketch.
+	randomized bool
+
+	// mu is the regularizer used by ApplyInverseTo,
+	// or zero for the default.
+	mu float64
+
+	// target and oversample are the rank and the oversampling
+	// of the randomized sketch, and next is the largest
+	// singular value of the sketch discarded by truncation.
+	target, oversample int
+	next               float64
+}
+
+// RSVDStats holds diagnostics of a randomized singular value decomposition.
+type RSVDStats struct {
+	// PowerIterations is the number of power
+	// iterations applied to the sketch.
+	PowerIterations int
+
+	// OrthoLoss is the largest ‖QᵀQ - I‖_F of the
+	// orthonormalized sketch bases over the power
+	// iterations, after reorthonormalization.
+	OrthoLoss float64
+
+	// OrthoLossExceeded is true if OrthoLoss is
+	// above rsvdOrthoTol, which indicates that the
+	// power iteration was numerically unstable for
+	// the input and that the factors may be degraded.
+	OrthoLossExceeded bool
+}
+
+// RSVDOption is a functional option for the randomized singular value decomposition.
+type RSVDOption func(*rsvdConfig)
+
+type rsvdConfig struct {
+	rnd           *rand.Rand
+	standardize   bool
+	accurateInner bool
+	innerGram     bool
+
+	pivoted  bool
+	pivotTol float64
+
+	orthoProjection bool
+	sketch          SketchKind
+
+	minRank int
+
+	colBlock int
+
+	powerIter    int
+	powerIterSet bool
+
+	seedFromInput bool
+
+	// oversample is the number of sketch columns
+	// used in addition to the requested rank.
+	oversample    int
+	oversampleSet bool
+
+	auto      bool
+	transpose bool
+
+	svTol float64
+
+	skipU, skipV bool
+
+	// adaptiveBlock is the number of columns added
+	// per step by the adaptive range finder.
+	adaptiveBlock int
+
+	adaptive bool
+	tol      float64
+
+	mu float64
+
+	logger *log.Logger
+}
+
+// withRand specifies the source of randomness for the projection.
+func withRand(rnd *rand.Rand) RSVDOption {
+	return func(c *rsvdConfig) { c.rnd = rnd }
+}
+
+// RSVDSource specifies the source of randomness used for the random
+// projection, making the decomposition reproducible for a seeded src.
+// Without RSVDSource the global rand source is used. Since a rand.Source
+// is not safe for concurrent use, each concurrent decomposition should use
+// its own source, for example one obtained from DeriveSource.
+func RSVDSource(src rand.Source) RSVDOption {
+	return withRand(rand.New(src))
+}
+
+// RSVDStandardize specifies that the columns of A are scaled to unit variance
+// before the decomposition is computed. The singular values and U are those
+// of the scaled matrix, while VTo returns V in the coordinate system of A, so
+// that A ≈ U Σ Vᵀ still holds but the columns of V are no longer orthonormal.
+// Columns of A with zero variance are not scaled. A is not modified.
+func RSVDStandardize() RSVDOption {
+	return func(c *rsvdConfig) { c.standardize = true }
+}
+
+// RSVDAccurateInner specifies that the SVD of the small rank×n projected
+// matrix is computed with one-sided Jacobi rotations instead of the default
+// bidiagonalization. The Jacobi method computes the small singular values of
+// matrices with a large dynamic range to high relative accuracy, at the cost
+// of being several times slower. It matters when the smallest retained
+// singular values are many orders of magnitude below the largest and their
+// relative accuracy is important; the accuracy of the large singular values
+// and of the randomized approximation itself is unchanged.
+func RSVDAccurateInner() RSVDOption {
+	return func(c *rsvdConfig) { c.accurateInner = true }
+}
+
+// RSVDInnerGram specifies that the inner singular value decomposition of the
+// rank×n projection Y = Qᵀ·A is computed from the eigendecomposition of the
+// rank×rank cross-product Y·Yᵀ = Uy Σ² Uyᵀ, with the right singular vectors
+// recovered as V = Yᵀ Uy Σ⁻¹, rather than by the bidiagonalization of Y. When
+// n is much larger than rank this replaces the O(rank²·n) bidiagonalization,
+// which is dominated by matrix-vector products, with two matrix multiplications
+// and is typically about twice as fast.
+//
+// Forming Y·Yᵀ squares the condition number of Y, so singular values less than
+// about √ε times the largest lose their relative accuracy and the corresponding
+// right singular vectors are no longer accurately orthogonal, where ε is the
+// machine epsilon. The leading singular triplets are unaffected. RSVDInnerGram
+// is ignored when RSVDAccurateInner is used.
+func RSVDInnerGram() RSVDOption {
+	return func(c *rsvdConfig) { c.innerGram = true }
+}
+
+// RSVDPivotedSketch specifies that the sketch Z = A·P is factorized with a
+// column-pivoted QR decomposition, and that columns of Z whose pivoted
+// diagonal element of R is less than tol times the largest are dropped as
+// numerically dependent. The rank of the factorization returned by Rank is
+// reduced accordingly. This makes the range basis better conditioned when
+// A is nearly rank deficient and a larger rank than its numerical rank was
+// requested. If tol is not positive, a tolerance of max(m,n)·ε is used.
+func RSVDPivotedSketch(tol float64) RSVDOption {
+	return func(c *rsvdConfig) {
+		c.pivoted = true
+		c.pivotTol = tol
+	}
+}
+
+// RSVDOrthoProjection specifies that the random projection matrix P is an
+// n×rank matrix with orthonormal columns, obtained from the QR factorization
+// of a Gaussian matrix, rather than a matrix with independent elements. The
+// orthonormal projection slightly reduces the variance of the recovered
+// spectrum when little or no oversampling is used.
+func RSVDOrthoProjection() RSVDOption {
+	return func(c *rsvdConfig) { c.orthoProjection = true }
+}
+
+// SketchKind specifies the random test matrix used to sketch the range of A.
+type SketchKind int
+
+const (
+	// SketchUniform uses a test matrix with independent elements
+	// drawn from the uniform distribution on [0, 1).
+	SketchUniform SketchKind = iota
+
+	// SketchGaussian uses a test matrix with independent elements
+	// drawn from the standard normal distribution. This is the test
+	// matrix analyzed by Halko, Martinsson and Tropp.
+	SketchGaussian
+
+	// SketchRademacher uses a test matrix with independent elements
+	// drawn from the uniform distribution on {-1, 1}, which behaves
+	// like a Gaussian test matrix and is cheaper to generate.
+	SketchRademacher
+
+	// SketchSRHT uses the subsampled randomized Hadamard transform
+	//  Ω = √(N/l) D H R,
+	// where N is the smallest power of two not less than n, D is a
+	// diagonal matrix of random signs, H is the orthogonal N×N
+	// Walsh–Hadamard matrix and R samples l columns. The sketch A·Ω
+	// is computed with a fast transform of each row of A in
+	// O(m·N·log N) operations instead of the O(m·n·l) of a matrix
+	// multiplication. It is preferable when l is large compared to
+	// log N, at the cost of needing slightly more oversampling than
+	// a Gaussian test matrix for the same accuracy.
+	SketchSRHT
+)
+
+// RSVDSketch specifies the kind of random test matrix used for the projection.
+// Without RSVDSketch, SketchUniform is used. RSVDSketch is ignored when
+// RSVDOrthoProjection is used. FactorizeOp, which can only access A through
+// matrix-vector products, forms the test matrix of SketchSRHT explicitly.
+// RSVDSketch will panic if kind is not a known SketchKind.
+func RSVDSketch(kind SketchKind) RSVDOption {
+	if kind < SketchUniform || SketchSRHT < kind {
+		panic("mat: unknown sketch kind")
+	}
+	return func(c *rsvdConfig) { c.sketch = kind }
+}
+
+// projection returns the n×k random projection matrix selected by c.
+func (c *rsvdConfig) projection(n, k int) *Dense {
+	if c.orthoProjection {
+		return orthonormalRandomMatrix(n, k, c.rnd)
+	}
+	switch c.sketch {
+	case SketchGaussian:
+		return makeRandomMatrix(n, k, distNormal, c.rnd)
+	case SketchRademacher:
+		return makeRandomMatrix(n, k, distRademacher, c.rnd)
+	case SketchSRHT:
+		return newSRHT(n, k, c.rnd).matrix()
+	default:
+		return makeRandomMatrix(n, k, distUniform, c.rnd)
+	}
+}
+
+// RSVDMinRank specifies the minimum rank k that may be requested from
+// Factorize, which otherwise panics. The default minimum rank is one.
+// Requiring a minimum rank also makes Factorize panic if k is greater than
+// min(m,n), since the factorization can then not have rank k.
+// RSVDMinRank will panic if k is less than one.
+func RSVDMinRank(k int) RSVDOption {
+	if k < 1 {
+		panic(fmt.Sprintf("mat: minimum rank %d is less than one", k))
+	}
+	return func(c *rsvdConfig) { c.minRank = k }
+}
+
+// RSVDColumnBlock specifies that the projection Y = Qᵀ·A is computed in
+// blocks of at most width columns of A. Blocks of A that cannot be viewed
+// directly are copied into a single m×width workspace, so the temporary
+// storage needed by the projection is bounded by m·width elements regardless
+// of the number of columns of A, at the cost of more, smaller products.
+// RSVDColumnBlock has no effect for band matrices, which are projected one
+// row of Y at a time. RSVDColumnBlock will panic if width is less than one.
+func RSVDColumnBlock(width int) RSVDOption {
+	if width < 1 {
+		panic("mat: non-positive column block width")
+	}
+	return func(c *rsvdConfig) { c.colBlock = width }
+}
+
+// RSVDPowerIter specifies that q power iterations are applied to the sketch,
+// so that the range basis is computed from (A Aᵀ)^q A P instead of A P. Each
+// iteration costs two further products with A, and improves the accuracy of
+// the decomposition when the singular values of A decay slowly. The basis is
+// orthonormalized after every product with A or Aᵀ, and reorthonormalized if
+// it lost orthogonality; the loss that remains is reported by Stats.
+// RSVDPowerIter will panic if q is negative.
+func RSVDPowerIter(q int) RSVDOption {
+	if q < 0 {
+		panic("mat: negative number of power iterations")
+	}
+	return func(c *rsvdConfig) {
+		c.powerIter = q
+		c.powerIterSet = true
+	}
+}
+
+// RSVDOversample specifies that the random projection has rank+p columns,
+// so that the sketch captures the range of A more reliably than a sketch with
+// exactly rank columns, and that only the leading rank singular triplets of the
+// oversampled factorization are returned. The number of sketch columns is at
+// most min(m,n). Halko, Martinsson and Tropp recommend p of 5 or 10, and the
+// probabilistic error bound evaluated by TheoreticalErrorBound requires p of at
+// least four. Without RSVDOversample or RSVDAuto no oversampling is used.
+// RSVDOversample will panic if p is negative.
+func RSVDOversample(p int) RSVDOption {
+	if p < 0 {
+		panic("mat: negative oversampling")
+	}
+	return func(c *rsvdConfig) {
+		c.oversample = p
+		c.oversampleSet = true
+	}
+}
+
+// RSVDSeedFromInput specifies that the source of randomness for the projection
+// is seeded from a hash of the dimensions of A and of a fixed sample of its
+// elements, so that factorizing the same matrix always gives the same result
+// without the caller managing seeds. The results are coupled to the exact bit
+// patterns of the sampled elements, so matrices that differ only in elements
+// that are not sampled share a seed, and any change to a sampled element,
+// however small, gives a different factorization. RSVDSeedFromInput overrides
+// any source of randomness given by other options.
+func RSVDSeedFromInput() RSVDOption {
+	return func(c *rsvdConfig) { c.seedFromInput = true }
+}
+
+// RSVDAuto specifies that the parameters of the randomized algorithm are chosen
+// from the dimensions m×n of A and the requested rank k. With l = min(k+10,
+// min(m,n)) sketch columns, the choices are
+//  oversampling:        l-k sketch columns beyond the rank
+//  power iterations:    0 if l = min(m,n), 1 if 4l >= min(m,n), otherwise 2
+//  orientation:         the transpose of A is factorized if m > n
+// Factorizing the transpose of a tall matrix computes the orthonormal basis of
+// the sketch from an n×l rather than an m×l matrix, reducing the cost of its
+// QR factorization. The transpose is not used for Symmetric inputs, or if the
+// columns of A are standardized.
+//
+// An explicitly specified oversampling or number of power iterations overrides
+// the choice made by RSVDAuto, regardless of the order of the options, and l is
+// then the oversampled sketch size.
+func RSVDAuto() RSVDOption {
+	return func(c *rsvdConfig) { c.auto = true }
+}
+
+// RSVDSVTol specifies that singular values of the decomposition less than
+// tol·σ_max, where σ_max is the largest singular value, are set to zero after
+// factorization. The zeroed values are then treated as zero by all the
+// accessors, so that the pseudoinverse and least squares solutions do not
+// divide by the tiny singular values a rank-deficient sketch produces. The
+// default tolerance is zero, which keeps all the computed singular values.
+// For noisy data a tolerance a few times the relative noise level is
+// recommended, and otherwise a small multiple of machine epsilon such as 1e-12.
+// RSVDSVTol will panic if tol is negative.
+func RSVDSVTol(tol float64) RSVDOption {
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	return func(c *rsvdConfig) { c.svTol = tol }
+}
+
+// RSVDSkipU specifies that the left singular vectors U are not needed, so they
+// are not computed by the inner SVD and UTo, and the accessors that use U,
+// will panic. Since U = Q Uy is only formed when it is extracted, the saving
+// is the computation and storage of the rank×rank inner factor Uy; the sketch
+// basis Q is retained for RangeError.
+func RSVDSkipU() RSVDOption {
+	return func(c *rsvdConfig) { c.skipU = true }
+}
+
+// RSVDSkipV specifies that the right singular vectors V are not needed, so
+// the inner SVD runs without forming Vᵀ, and VTo, and the accessors that use
+// V, will panic. This saves the computation of the rank×n matrix Vᵀ in the
+// inner SVD and its storage, which dominates the cost of the inner SVD when n
+// is large, for example when U is only used to project data.
+//
+// When either U or V is skipped, RSVDAuto does not choose to factorize the
+// transpose of A, since both factors are needed to recover the factorization
+// of A from that of its transpose.
+func RSVDSkipV() RSVDOption {
+	return func(c *rsvdConfig) { c.skipV = true }
+}
+
+// RSVDVectors specifies which singular vectors are computed, using the
+// SVDKind values of the exact SVD. SVDNone computes only the singular values,
+// SVDThinU and SVDThinV compute only U or only V, as RSVDSkipV and RSVDSkipU
+// do, and SVDThin, the default, computes both. With SVDNone the inner SVD
+// forms neither factor and, for Symmetric A, the eigenvectors of the projected
+// matrix are not computed, which suits inspection of the spectrum.
+//
+// RSVDVectors will panic if kind is SVDFull or includes a full factor.
+func RSVDVectors(kind SVDKind) RSVDOption {
+	if kind&^SVDThin != 0 {
+		panic("mat: full singular vectors not supported by RSVD")
+	}
+	return func(c *rsvdConfig) {
+		c.skipU = kind&SVDThinU == 0
+		c.skipV = kind&SVDThinV == 0
+	}
+}
+
+// rsvdAdaptiveBlock is the default number of columns added
+// per step by the adaptive range finder.
+const rsvdAdaptiveBlock = 10
+
+// RSVDAdaptiveBlock specifies that the adaptive range finder grows the sketch
+// by b columns per step. Each step orthonormalizes the new columns against the
+// current basis, so larger blocks amortize the orthonormalization over more
+// columns and use matrix-matrix rather than matrix-vector products with A, at
+// the cost of overshooting the smallest sufficient rank by up to b-1 columns.
+// The default block size is 10. RSVDAdaptiveBlock only affects factorizations
+// that determine their rank adaptively with RSVDTolerance. RSVDAdaptiveBlock
+// will panic if b is less than one.
+func RSVDAdaptiveBlock(b int) RSVDOption {
+	if b < 1 {
+		panic("mat: adaptive block size must be positive")
+	}
+	return func(c *rsvdConfig) { c.adaptiveBlock = b }
+}
+
+// RSVDTolerance specifies that Factorize determines the rank of the
+// factorization adaptively, so that the Frobenius norm error of the
+// approximation,
+//  ‖A - U Σ Vᵀ‖_F,
+// is at most tol, with the rank passed to Factorize as an upper bound of the
+// rank. The range basis Q is grown from Gaussian sketches in blocks of
+// RSVDAdaptiveBlock columns until the range error ‖A - Q Qᵀ A‖_F, which is
+// tracked exactly from the energy of A captured by Q, is at most tol, and the
+// factorization is then truncated to the smallest rank that keeps the total
+// error within tol. The discovered rank is returned by Rank. If the tolerance
+// cannot be reached within the upper bound, the rank is the upper bound, and
+// RangeError reports the error that was achieved. Since the error is tracked
+// by subtraction, tolerances below about √ε·‖A‖_F cannot be resolved.
+//
+// RSVDTolerance is used with the options RSVDSource, RSVDAdaptiveBlock,
+// RSVDAccurateInner, RSVDInnerGram, RSVDSVTol, RSVDSkipU, RSVDSkipV and
+// RSVDRegularizer; the other options controlling the sketch are ignored, and
+// symmetric inputs are factorized as general matrices. RSVDTolerance will
+// panic if tol is negative.
+func RSVDTolerance(tol float64) RSVDOption {
+	if tol < 0 {
+		panic("mat: negative tolerance")
+	}
+	return func(c *rsvdConfig) {
+		c.adaptive = true
+		c.tol = tol
+	}
+}
+
+// RSVDRegularizer specifies the regularizer μ of the inverse (Â + μI)⁻¹ of the
+// approximation Â of a square A applied by ApplyInverseTo. Without
+// RSVDRegularizer the smallest retained singular value σ_rank is used, which
+// leaves the directions outside the range of the factors scaled like the
+// weakest captured direction. RSVDRegularizer will panic if mu is not positive.
+func RSVDRegularizer(mu float64) RSVDOption {
+	if !(mu > 0) {
+		panic("mat: regularizer must be positive")
+	}
+	return func(c *rsvdConfig) { c.mu = mu }
+}
+
+// RSVDLogger specifies that warnings about conditions that may degrade the
+// factorization are written to l. Factorize warns when A contains a NaN, when
+// it falls back to the deterministic SVD because the rank is at least
+// min(m,n), and when the power iteration loses orthogonality beyond the level
+// reported by RSVDStats.OrthoLossExceeded. FactorizeOp warns about the loss of
+// orthogonality. Each warning is a single line of space-separated key=value
+// pairs. Checking A for NaN values reads all its elements, which is only done
+// when a logger is set. Without RSVDLogger no warnings are written and the
+// factorization is unchanged.
+func RSVDLogger(l *log.Logger) RSVDOption {
+	return func(c *rsvdConfig) { c.logger = l }
+}
+
+// warn writes a warning with the given message and formatted key=value
+// pairs to the logger of the configuration, if one is set.
+func (c *rsvdConfig) warn(msg, format string, args ...interface{}) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.Printf("mat: rsvd warning=%q "+format, append([]interface{}{msg}, args...)...)
+}
+
+// svdKind returns the kind of the inner singular value decomposition.
+func (c *rsvdConfig) svdKind() SVDKind {
+	kind := SVDThin
+	if c.skipU {
+		kind &^= SVDThinU
+	}
+	if c.skipV {
+		kind &^= SVDThinV
+	}
+	return kind
+}
+
+// applyAuto sets the parameters chosen by RSVDAuto for
+// the rank k factorization of the m×n matrix a.
+func (c *rsvdConfig) applyAuto(a Matrix, m, n, k int) {
+	mn := min(m, n)
+	if !c.oversampleSet {
+		c.oversample = min(k+10, mn) - k
+	}
+	l := min(k+c.oversample, mn)
+	if !c.powerIterSet {
+		switch {
+		case l == mn:
+			c.powerIter = 0
+		case 4*l >= mn:
+			c.powerIter = 1
+		default:
+			c.powerIter = 2
+		}
+	}
+	_, isSym := a.(Symmetric)
+	c.transpose = m > n && !isSym && !c.standardize && !c.skipU && !c.skipV
 }
 
 // Factorize computes the randomized singular value decomposition (RSVD) of the input matrix A
 // using randomized matrix rank × rank
 //
+// The behavior of Factorize may be modified by the provided options.
+//
+// If A is a band matrix, the projections A·P and Qᵀ·A are computed with
+// banded matrix-vector products rather than a general matrix multiply.
+//
 // Factorize returns whether the decomposition succeeded. If the decomposition
 // failed, routines that require a successful factorization will panic.
-// Factorize will also panic if rank is too low
-func (rsvd *RSVD) Factorize(A Matrix, rank int) bool {
+// Factorize will also panic with ErrShape if A has a zero dimension, and
+// if rank is less than the minimum rank, which is one unless set by
+// RSVDMinRank.
+//
+// If A is Symmetric, and the columns of A are not standardized, Factorize
+// computes a randomized eigendecomposition A ≈ U Λ Uᵀ instead, so that the
+// singular values are the magnitudes of the eigenvalues in Λ and V is
+// exactly U with the columns corresponding to negative eigenvalues negated.
+// The signed eigenvalues are then available from the Eigenvalues method.
+//
+// If rank is at least min(m,n), the randomized sketch cannot improve on the
+// exact decomposition, so Factorize computes the deterministic thin SVD of A
+// instead and the rank of the factorization is min(m,n). In this case the
+// results do not depend on the random projection. The rank plus oversampling
+// is likewise limited to min(m,n), so for a wide matrix with m < n the sketch
+// has at most m columns and U is square.
+func (rsvd *RSVD) Factorize(A Matrix, rank int, opts ...RSVDOption) bool {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
-	const minRank = 1
+	// Dimensions of input matrix:
+	// [A] = m × n
+	m, n := A.Dims()
+
+	if cfg.seedFromInput {
+		cfg.rnd = rand.New(rand.NewSource(inputSeed(A)))
+	}
 
+	minRank := 1
+	if cfg.minRank > 0 {
+		minRank = cfg.minRank
+	}
+
+	if m == 0 || n == 0 {
+		panic(ErrShape)
+	}
 	// Check if rank is too small
 	if rank < minRank {
-		panic(fmt.Sprintf("Rank %d must be at least %d", rank, minRank))
+		panic(fmt.Sprintf("mat: rank %d for %d×%d matrix is less than the minimum rank %d", rank, m, n, minRank))
+	}
+	if cfg.minRank > 0 && minRank > min(m, n) {
+		panic(fmt.Sprintf("mat: minimum rank %d for %d×%d matrix is greater than min(m,n) = %d", minRank, m, n, min(m, n)))
 	}
 
-	// Dimensions of input matrix:
-	// [A] = m × n
+	if cfg.logger != nil && hasNaN(A) {
+		cfg.warn("NaN in input", "rows=%d cols=%d", m, n)
+	}
+	if rank >= min(m, n) && !cfg.adaptive {
+		cfg.warn("fallback to deterministic SVD", "rank=%d min_dim=%d", rank, min(m, n))
+	}
+
+	if cfg.auto {
+		cfg.applyAuto(A, m, n, rank)
+	}
+	if cfg.adaptive {
+		if !rsvd.factorizeAdaptive(A, min(rank, min(m, n)), cfg) {
+			return false
+		}
+	} else if cfg.transpose && rank < min(m, n) {
+		cfg.transpose = false
+		if !rsvd.factorize(A.T(), rank, cfg) {
+			return false
+		}
+		rsvd.untranspose()
+	} else if !rsvd.factorize(A, rank, cfg) {
+		return false
+	}
+	rsvd.warnOrthoLoss(&cfg)
+	rsvd.zeroSmallValues(cfg.svTol)
+	rsvd.dropFactors(cfg.svdKind())
+	rsvd.mu = cfg.mu
+	return true
+}
+
+// RSVDSettings holds the commonly tuned parameters of the randomized singular
+// value decomposition computed by RSVD.FactorizeWith. The zero value gives the
+// defaults of Factorize without options: no oversampling, no power
+// iterations, a uniform test matrix, the global rand source and both sets of
+// singular vectors.
+type RSVDSettings struct {
+	// Oversample is the number of sketch columns used in
+	// addition to the rank, as for RSVDOversample.
+	Oversample int
+
+	// PowerIter is the number of power iterations applied
+	// to the sketch, as for RSVDPowerIter.
+	PowerIter int
+
+	// Sketch is the distribution of the test matrix,
+	// as for RSVDSketch.
+	Sketch SketchKind
+
+	// Src is the source of randomness for the test matrix.
+	// If Src is nil, the global rand source is used.
+	Src rand.Source
+
+	// SkipU and SkipV specify that the left or right singular
+	// vectors are not computed, as for RSVDSkipU and RSVDSkipV.
+	// If both are set, only the singular values are computed.
+	SkipU, SkipV bool
+}
+
+// options returns the options equivalent to the settings.
+func (s *RSVDSettings) options() []RSVDOption {
+	// Zero values are left unset, so that RSVDAuto
+	// passed with the settings may choose them.
+	opts := []RSVDOption{RSVDSketch(s.Sketch)}
+	if s.Oversample != 0 {
+		opts = append(opts, RSVDOversample(s.Oversample))
+	}
+	if s.PowerIter != 0 {
+		opts = append(opts, RSVDPowerIter(s.PowerIter))
+	}
+	if s.Src != nil {
+		opts = append(opts, RSVDSource(s.Src))
+	}
+	if s.SkipU {
+		opts = append(opts, RSVDSkipU())
+	}
+	if s.SkipV {
+		opts = append(opts, RSVDSkipV())
+	}
+	return opts
+}
+
+// FactorizeWith computes the randomized singular value decomposition of A
+// using the parameters in settings, and is otherwise equivalent to Factorize
+// with the corresponding options. If settings is nil, the zero value of
+// RSVDSettings is used. Options that are not covered by RSVDSettings may be
+// passed in opts, and are applied after the settings.
+//
+// FactorizeWith panics under the same conditions as Factorize, if settings
+// holds a negative Oversample or PowerIter, or if Sketch is not a known
+// SketchKind.
+func (rsvd *RSVD) FactorizeWith(A Matrix, rank int, settings *RSVDSettings, opts ...RSVDOption) bool {
+	if settings == nil {
+		return rsvd.Factorize(A, rank, opts...)
+	}
+	return rsvd.Factorize(A, rank, append(settings.options(), opts...)...)
+}
+
+// FactorizeOp computes the randomized singular value decomposition of the m×n
+// operator A that is only accessed through its products with vectors, for
+// example an operator returned by NewLowRankPlusDiagOp. The sketch A·P, the
+// power iterations and the projection Qᵀ·A = (Aᵀ·Q)ᵀ are each computed with
+// rank plus oversampling products with A or Aᵀ, so A is never formed.
+//
+// The options RSVDSource, RSVDOversample, RSVDPowerIter, RSVDSketch,
+// RSVDOrthoProjection, RSVDAccurateInner, RSVDSVTol, RSVDSkipU, RSVDSkipV and
+// RSVDRegularizer have the same effect as for Factorize. The options that
+// require access to the elements of A are ignored.
+//
+// FactorizeOp returns whether the decomposition succeeded. If the
+// decomposition failed, routines that require a successful factorization will
+// panic. FactorizeOp will panic if rank is not in [1, min(m,n)].
+func (rsvd *RSVD) FactorizeOp(A LinearOp, rank int, opts ...RSVDOption) bool {
+	var cfg rsvdConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	m, n := A.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic(ErrShape)
+	}
+
+	rsvd.scale = nil
+	rsvd.eig = nil
+	rsvd.stats = RSVDStats{}
+	rsvd.randomized = true
+	if rsvd.svd == nil {
+		rsvd.svd = &SVD{}
+	}
+	target := rank
+	rank = min(rank+cfg.oversample, min(m, n))
+
+	// [Z] = [A × P] = m × rank
+	var Z Dense
+	mulOp(&Z, A, false, cfg.projection(n, rank))
+	if isZeroDense(&Z) {
+		rsvd.factorizeZero(m, n, target)
+		rsvd.dropFactors(cfg.svdKind())
+		rsvd.mu = cfg.mu
+		return true
+	}
+
+	// [Z] = [(A × Aᵀ)^q × A × P] = m × rank
+	var W Dense
+	for it := 0; it < cfg.powerIter; it++ {
+		mulOp(&W, A, true, orthonormalize(&Z, &rsvd.stats))
+		mulOp(&Z, A, false, orthonormalize(&W, &rsvd.stats))
+	}
+	if cfg.powerIter > 0 {
+		rsvd.stats.PowerIterations = cfg.powerIter
+		rsvd.stats.OrthoLossExceeded = rsvd.stats.OrthoLoss > rsvdOrthoTol
+	}
+
+	// [Y] = [Qᵀ × A] = [(Aᵀ × Q)ᵀ] = rank × n
+	Q := orthonormalBasis(&Z)
+	var Yt Dense
+	mulOp(&Yt, A, true, Q)
+
+	rsvd.m, rsvd.n = m, n
+	rsvd.rank = rank
+	rsvd.q = Q
+
+	if !rsvd.factorizeInner(DenseCopyOf(Yt.T()), cfg) {
+		return false
+	}
+	rsvd.recordSketch(target)
+	rsvd.truncate(target)
+	rsvd.warnOrthoLoss(&cfg)
+	rsvd.zeroSmallValues(cfg.svTol)
+	rsvd.dropFactors(cfg.svdKind())
+	rsvd.mu = cfg.mu
+	return true
+}
+
+// warnOrthoLoss warns if the power iteration lost orthogonality.
+func (rsvd *RSVD) warnOrthoLoss(cfg *rsvdConfig) {
+	if rsvd.stats.OrthoLossExceeded {
+		cfg.warn("loss of orthogonality", "ortho_loss=%.3g tol=%g power_iter=%d",
+			rsvd.stats.OrthoLoss, rsvdOrthoTol, rsvd.stats.PowerIterations)
+	}
+}
+
+// factorize computes the randomized singular value decomposition of A
+// with the given configuration after the rank has been validated.
+func (rsvd *RSVD) factorize(A Matrix, rank int, cfg rsvdConfig) bool {
 	m, n := A.Dims()
 
-	// Create random matrix:
-	// [P] = n × rank
-	P := makeRandomMatrix(n, rank)
+	rsvd.scale = nil
+	if cfg.standardize {
+		rsvd.scale = colStdDevs(A)
+	}
+	rsvd.eig = nil
+	rsvd.stats = RSVDStats{}
+	rsvd.randomized = false
+	sym, isSym := A.(Symmetric)
+	isSym = isSym && rsvd.scale == nil
+	if rsvd.svd == nil {
+		rsvd.svd = &SVD{}
+	}
+
+	// A sketch with at least min(m,n) columns spans the entire range
+	// of A, so randomization gains nothing and only adds variance.
+	if rank >= min(m, n) {
+		if isSym {
+			rsvd.m, rsvd.n = m, n
+			rsvd.rank = m
+			rsvd.q = nil
+			return rsvd.factorizeSym(sym, cfg.svdKind() != SVDNone)
+		}
+		return rsvd.factorizeFull(A, cfg.svdKind())
+	}
+	rsvd.randomized = true
+
+	// The sketch is oversampled and truncated to the target rank
+	// after the inner factorization.
+	target := rank
+	rank = min(rank+cfg.oversample, min(m, n))
 
 	// Project random matrix P into original M:
 	// [Z] = [M × P] = (m × n) × (n × rank) = m × rank
 	Z := NewDense(m, rank, nil)
-	Z.Mul(A, P)
+	if cfg.sketch == SketchSRHT && !cfg.orthoProjection {
+		newSRHT(n, rank, cfg.rnd).sketch(Z, A, rsvd.scale)
+	} else {
+		// Create random matrix:
+		// [P] = n × rank
+		P := cfg.projection(n, rank)
+
+		// Scaling the columns of A is equivalent to scaling the rows of P
+		// and the columns of Y, so A itself is never copied:
+		// [A × D⁻¹ × P] = [A × (D⁻¹ × P)]
+		for i, s := range rsvd.scale {
+			row := P.rawRowView(i)
+			for j := range row {
+				row[j] /= s
+			}
+		}
+		sketchMul(Z, A, P)
+	}
+
+	// A zero sketch means that A is zero with probability one, so store
+	// the exact zero decomposition with canonical singular vectors rather
+	// than relying on the QR and SVD treatment of a zero input.
+	if isZeroDense(Z) {
+		rsvd.factorizeZero(m, n, target)
+		if isSym {
+			rsvd.eig = make([]float64, target)
+		}
+		return true
+	}
+
+	// Apply power iterations to the sketch:
+	// [Z] = [(M × Mᵀ)^q × M × P] = m × rank
+	if cfg.powerIter > 0 {
+		powerIterate(Z, A, cfg.powerIter, rsvd.scale, &rsvd.stats)
+	}
 
-	// Factorize M into orthogonal Q and triangular R:
-	// [QFull] = m × m
-	var QFull Dense
-	QR := QR{}
+	// Drop the numerically dependent columns of the sketch:
+	// [Z] = m × k, k <= rank
+	if cfg.pivoted {
+		Z, rank = independentColumns(Z, cfg.pivotTol)
+	}
+
+	// Factorize M into orthogonal Q and triangular R, forming
+	// only the leading columns of Q:
+	// [Q] = m × rank
+	var QR QR
 	QR.Factorize(Z)
-	QR.QTo(&QFull)
+	Q := &Dense{}
+	QR.thinQTo(Q)
+
+	// Project M into Q:
+	// [Y] = [Qᵀ × M] = (rank × m) × (m × n) = rank × n
+	Y := NewDense(rank, n, nil)
+	if cfg.colBlock > 0 {
+		projectMulColumnBlocked(Y, Q, A, cfg.colBlock)
+	} else {
+		projectMul(Y, Q, A)
+	}
+	for j, s := range rsvd.scale {
+		for i := 0; i < rank; i++ {
+			Y.set(i, j, Y.at(i, j)/s)
+		}
+	}
+
+	rsvd.m, rsvd.n = m, n
+	rsvd.rank = rank
+	rsvd.q = Q
+
+	if isSym {
+		// Project A onto Q from both sides:
+		// [T] = [Qᵀ × A × Q] = [Y × Q] = rank × rank
+		var T Dense
+		T.Mul(Y, Q)
+		Tsym := NewSymDense(rank, nil)
+		for i := 0; i < rank; i++ {
+			for j := i; j < rank; j++ {
+				Tsym.SetSym(i, j, 0.5*(T.at(i, j)+T.at(j, i)))
+			}
+		}
+		if !rsvd.factorizeSym(Tsym, cfg.svdKind() != SVDNone) {
+			return false
+		}
+		rsvd.recordSketch(target)
+		rsvd.truncate(target)
+		return true
+	}
+
+	// Perform SVD for Y:
+	// [Y] = [Uy × Σ × V] = (rank × rank) × (rank × rank) × (rank × n) = rank × n
+	if !rsvd.factorizeInner(Y, cfg) {
+		return false
+	}
+	rsvd.recordSketch(target)
+	rsvd.truncate(target)
+	return true
+}
+
+// factorizeAdaptive computes the factorization of A with the smallest rank
+// not greater than maxRank whose error is at most cfg.tol.
+func (rsvd *RSVD) factorizeAdaptive(A Matrix, maxRank int, cfg rsvdConfig) bool {
+	m, n := A.Dims()
+	rsvd.scale = nil
+	rsvd.eig = nil
+	rsvd.stats = RSVDStats{}
+	if rsvd.svd == nil {
+		rsvd.svd = &SVD{}
+	}
+	block := cfg.adaptiveBlock
+	if block == 0 {
+		block = rsvdAdaptiveBlock
+	}
+	Q, resid := adaptiveRangeFinder(A, cfg.tol, block, maxRank, cfg.rnd)
+	if Q == nil {
+		rsvd.factorizeZero(m, n, 1)
+		return true
+	}
+	_, k := Q.Dims()
+
+	// [Y] = [Qᵀ × A] = k × n
+	Y := NewDense(k, n, nil)
+	projectMul(Y, Q, A)
+	if !rsvd.factorizeInner(Y, cfg) {
+		return false
+	}
+	rsvd.m, rsvd.n = m, n
+	rsvd.rank = k
+	rsvd.q = Q
+	rsvd.randomized = true
+
+	// The error of the rank-r truncation of Q Y is
+	// ‖A - Q Qᵀ A‖_F² + σ_{r+1}² + ... + σ_k².
+	s := rsvd.svd.s
+	bound := cfg.tol * cfg.tol
+	tail := resid * resid
+	r := k
+	for r > 1 && tail+s[r-1]*s[r-1] <= bound {
+		tail += s[r-1] * s[r-1]
+		r--
+	}
+	rsvd.recordSketch(r)
+	rsvd.truncate(r)
+	return true
+}
+
+// factorizeInner computes the singular value decomposition of the projection
+// y = Qᵀ·A into the receiver using the method selected by cfg.
+func (rsvd *RSVD) factorizeInner(y *Dense, cfg rsvdConfig) bool {
+	l, n := y.Dims()
+	switch {
+	case cfg.accurateInner:
+		return rsvd.svd.factorizeJacobi(y)
+	case cfg.innerGram && l < n:
+		return rsvd.svd.factorizeGram(y)
+	default:
+		return rsvd.svd.Factorize(y, cfg.svdKind())
+	}
+}
+
+// factorizeGram computes the thin singular value decomposition of the l×n
+// matrix y with l < n from the eigendecomposition of y·yᵀ.
+func (svd *SVD) factorizeGram(y *Dense) bool {
+	l, n := y.Dims()
+	var g SymDense
+	g.SymOuterK(1, y)
+	var eig EigenSym
+	if !eig.Factorize(&g, true) {
+		return false
+	}
+	var w Dense
+	eig.VectorsTo(&w)
+
+	// The rows of [Wᵀ × Y] = l × n are the right singular
+	// vectors scaled by the singular values.
+	var wty Dense
+	wty.Mul(w.T(), y)
+	norms := make([]float64, l)
+	idx := make([]int, l)
+	for i := range norms {
+		norms[i] = floats.Norm(wty.rawRowView(i), 2)
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return norms[idx[i]] > norms[idx[j]] })
+
+	u := NewDense(l, l, nil)
+	vt := NewDense(l, n, nil)
+	sv := make([]float64, l)
+	for k, i := range idx {
+		sv[k] = norms[i]
+		for r := 0; r < l; r++ {
+			u.set(r, k, w.at(r, i))
+		}
+		if norms[i] != 0 {
+			floats.ScaleTo(vt.rawRowView(k), 1/norms[i], wty.rawRowView(i))
+		}
+	}
+	*svd = SVD{
+		kind: SVDThin,
+		s:    sv,
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+	return true
+}
+
+// recordSketch records the target rank k and the oversampling of the sketch,
+// and the largest singular value that truncation to rank k discards.
+func (rsvd *RSVD) recordSketch(k int) {
+	rsvd.target = k
+	rsvd.oversample = rsvd.rank - k
+	rsvd.next = 0
+	if k < len(rsvd.svd.s) {
+		rsvd.next = rsvd.svd.s[k]
+	}
+}
+
+// truncate discards all but the leading k singular values and vectors
+// of an oversampled factorization.
+func (rsvd *RSVD) truncate(k int) {
+	if k >= rsvd.rank {
+		return
+	}
+	rsvd.rank = k
+	rsvd.svd.s = rsvd.svd.s[:k]
+	rsvd.svd.u.Cols = k
+	rsvd.svd.vt.Rows = k
+	if rsvd.eig != nil {
+		rsvd.eig = rsvd.eig[:k]
+	}
+}
+
+// zeroSmallValues sets the singular values less than tol times the largest
+// singular value, and the corresponding eigenvalues, to zero.
+func (rsvd *RSVD) zeroSmallValues(tol float64) {
+	s := rsvd.svd.s
+	if tol == 0 || len(s) == 0 {
+		return
+	}
+	// The singular values are in descending order.
+	thresh := tol * s[0]
+	for i := len(s) - 1; i >= 0 && s[i] < thresh; i-- {
+		s[i] = 0
+		if rsvd.eig != nil {
+			rsvd.eig[i] = 0
+		}
+	}
+}
+
+// dropFactors releases the singular vectors that are not included in kind,
+// which the paths computing both factors regardless of kind may have formed.
+func (rsvd *RSVD) dropFactors(kind SVDKind) {
+	if kind&SVDThinU == 0 {
+		rsvd.svd.u = blas64.General{}
+	}
+	if kind&SVDThinV == 0 {
+		rsvd.svd.vt = blas64.General{}
+	}
+	rsvd.svd.kind = kind
+}
+
+// hasU and hasV return whether the left and right
+// singular vectors were computed during factorization.
+func (rsvd *RSVD) hasU() bool { return rsvd.svd.kind&SVDThinU != 0 }
+func (rsvd *RSVD) hasV() bool { return rsvd.svd.kind&SVDThinV != 0 }
+
+// untranspose converts the factorization of Aᵀ held by the receiver into
+// the factorization of A, storing the lifted singular vectors explicitly.
+func (rsvd *RSVD) untranspose() {
+	var u, v Dense
+	rsvd.VTo(&u)
+	rsvd.UTo(&v)
+	var vt Dense
+	vt.CloneFrom(v.T())
+	rsvd.m, rsvd.n = u.mat.Rows, v.mat.Rows
+	rsvd.q = nil
+	*rsvd.svd = SVD{
+		kind: SVDThin,
+		s:    rsvd.svd.s,
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+}
+
+// seedSamples is the number of elements of a matrix sampled by inputSeed.
+const seedSamples = 64
+
+// inputSeed returns a seed derived from the dimensions of a and the bits of
+// up to seedSamples of its elements taken at evenly spaced positions in
+// row-major order.
+func inputSeed(a Matrix) uint64 {
+	r, c := a.Dims()
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	write(uint64(r))
+	write(uint64(c))
+	size := r * c
+	samples := min(seedSamples, size)
+	for k := 0; k < samples; k++ {
+		idx := int(int64(k) * int64(size) / int64(samples))
+		write(math.Float64bits(a.At(idx/c, idx%c)))
+	}
+	return h.Sum64()
+}
+
+// rsvdOrthoTol is the loss of orthogonality ‖QᵀQ - I‖_F of a
+// reorthonormalized basis above which RSVDStats reports a warning.
+const rsvdOrthoTol = 1e-10
+
+// powerIterate replaces the sketch z of A with (A Aᵀ)^q z, orthonormalizing
+// the iterates between products. The columns of A are scaled by scale, which
+// may be nil. If stats is not nil, the number of iterations and the loss of
+// orthogonality are recorded in it.
+func powerIterate(z *Dense, a Matrix, q int, scale []float64, stats *RSVDStats) {
+	m, k := z.Dims()
+	_, n := a.Dims()
+	wt := NewDense(k, n, nil)
+	for it := 0; it < q; it++ {
+		// [W] = [Mᵀ × orth(Z)] = n × rank
+		qz := orthonormalize(z, stats)
+		projectMul(wt, qz, a)
+		for j, s := range scale {
+			for i := 0; i < k; i++ {
+				wt.set(i, j, wt.at(i, j)/s)
+			}
+		}
+
+		// [Z] = [M × orth(W)] = m × rank
+		qw := orthonormalize(DenseCopyOf(wt.T()), stats)
+		for i, s := range scale {
+			row := qw.rawRowView(i)
+			for j := range row {
+				row[j] /= s
+			}
+		}
+		z.reuseAsNonZeroed(m, k)
+		sketchMul(z, a, qw)
+	}
+	if stats != nil {
+		stats.PowerIterations = q
+		stats.OrthoLossExceeded = stats.OrthoLoss > rsvdOrthoTol
+	}
+}
+
+// orthonormalize returns an orthonormal basis for the range of the r×c
+// matrix z with r >= c, reorthonormalizing it once if it lost orthogonality.
+// If stats is not nil, the remaining loss is recorded in it.
+func orthonormalize(z *Dense, stats *RSVDStats) *Dense {
+	q := orthonormalBasis(z)
+	loss := orthoError(q)
+	if loss > rsvdOrthoTol {
+		q = orthonormalBasis(q)
+		loss = orthoError(q)
+	}
+	if stats != nil {
+		stats.OrthoLoss = math.Max(stats.OrthoLoss, loss)
+	}
+	return q
+}
+
+// orthonormalBasis returns the r×c orthonormal factor of the QR
+// factorization of the r×c matrix z with r >= c.
+func orthonormalBasis(z *Dense) *Dense {
+	var qr QR
+	qr.Factorize(z)
+	var q Dense
+	qr.thinQTo(&q)
+	return &q
+}
+
+// thinQR returns the thin QR factorization a = q r of the m×k matrix a with
+// k <= m, where q is m×k with orthonormal columns and r is k×k upper triangular.
+func thinQR(a *Dense) (q, r *Dense) {
+	_, k := a.Dims()
+	var qr QR
+	qr.Factorize(a)
+	var rFull Dense
+	q = &Dense{}
+	qr.thinQTo(q)
+	qr.RTo(&rFull)
+	return q, rFull.slice(0, k, 0, k)
+}
+
+// adaptiveRangeFinder returns an m×k orthonormal basis q of an approximate
+// range of the m×n matrix a, grown block columns at a time from Gaussian
+// sketches, with k the smallest multiple of block, or maxCols, for which the
+// range error
+//  ‖(I - q qᵀ) a‖_F
+// is at most tol, and the range error of the returned basis. This is the blocked
+// variant of the adaptive randomized range finder of Halko, Martinsson and
+// Tropp (Algorithm 4.2), where the error is not estimated from probe vectors
+// but tracked exactly as the energy of a not captured by the basis,
+//  ‖a‖_F² - ‖qᵀ a‖_F²,
+// by the method of Yu, Gu and Li (randQB_EI). The subtraction limits the
+// attainable tolerance to about √ε·‖a‖_F. Each new block is orthogonalized
+// against the basis twice to maintain orthogonality. If a is zero,
+// adaptiveRangeFinder returns nil and zero. maxCols must not be greater than min(m,n).
+func adaptiveRangeFinder(a Matrix, tol float64, block, maxCols int, rnd *rand.Rand) (q *Dense, resid float64) {
+	m, n := a.Dims()
+	norm := Norm(a, 2)
+	if norm == 0 {
+		return nil, 0
+	}
+	energy := norm * norm
+
+	basis := NewDense(m, maxCols, nil)
+	var k int
+	for k < maxCols && math.Sqrt(math.Max(energy, 0)) > tol {
+		b := min(block, maxCols-k)
+		var y Dense
+		y.Mul(a, makeRandomMatrix(n, b, distNormal, rnd))
+		qb := &y
+		for pass := 0; pass < 2; pass++ {
+			if k > 0 {
+				qk := basis.slice(0, m, 0, k)
+				var c, qc Dense
+				c.Mul(qk.T(), qb)
+				qc.Mul(qk, &c)
+				qb.Sub(qb, &qc)
+			}
+			qb = orthonormalBasis(qb)
+		}
+		var bb Dense
+		bb.Mul(qb.T(), a)
+		captured := Norm(&bb, 2)
+		energy -= captured * captured
+
+		basis.slice(0, m, k, k+b).Copy(qb)
+		k += b
+	}
+	return basis.slice(0, m, 0, k), math.Sqrt(math.Max(energy, 0))
+}
+
+// orthoError returns ‖QᵀQ - I‖_F.
+func orthoError(q *Dense) float64 {
+	_, c := q.Dims()
+	var qtq Dense
+	qtq.Mul(q.T(), q)
+	for i := 0; i < c; i++ {
+		qtq.set(i, i, qtq.at(i, i)-1)
+	}
+	return Norm(&qtq, 2)
+}
+
+// orthonormalRandomMatrix returns a rows×cols matrix with orthonormal columns
+// spanning the range of a Gaussian random matrix. It requires cols <= rows.
+func orthonormalRandomMatrix(rows, cols int, rnd *rand.Rand) *Dense {
+	return orthonormalBasis(makeRandomMatrix(rows, cols, distNormal, rnd))
+}
+
+// independentColumns returns the columns of z that are numerically
+// independent according to a column-pivoted QR factorization, together with
+// their number. A column is dependent if its pivoted diagonal element of R is
+// less than tol times the largest. If tol is not positive, a tolerance of
+// max(m,n)·ε is used.
+func independentColumns(z *Dense, tol float64) (*Dense, int) {
+	m, n := z.Dims()
+	if tol <= 0 {
+		tol = float64(max(m, n)) * (1.0 / (1 << 53))
+	}
+	r, piv := pivotedQR(z, n)
+	k := 1
+	for k < n && math.Abs(r.at(k, k)) > tol*math.Abs(r.at(0, 0)) {
+		k++
+	}
+	if k == n {
+		return z, n
+	}
+	sel := NewDense(m, k, nil)
+	for j := 0; j < k; j++ {
+		for i := 0; i < m; i++ {
+			sel.set(i, j, z.at(i, piv[j]))
+		}
+	}
+	return sel, k
+}
+
+// Dims returns the dimensions of the factorized matrix A.
+//
+// Dims will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) Dims() (m, n int) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	return rsvd.m, rsvd.n
+}
+
+// Rank returns the number of singular values and vectors of the factorization.
+//
+// Rank will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) Rank() int {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	return rsvd.rank
+}
+
+// RankForError returns the smallest rank k for which truncating the
+// factorization to its leading k singular values keeps the relative
+// Frobenius norm error with respect to the retained approximation within eps,
+//  σ_{k+1}² + ... + σ_rank² <= eps² (σ_1² + ... + σ_rank²).
+// The tail energy is measured against the energy captured by the
+// factorization, not against ‖A‖_F², so the error of the truncated
+// factorization with respect to A also includes the error of the sketch.
+// RankForError returns at least one, and is typically used with TruncateTo as
+//  rsvd.TruncateTo(rsvd.RankForError(eps))
+//
+// RankForError will panic if eps is negative or if the receiver does not
+// contain a successful factorization.
+func (rsvd *RSVD) RankForError(eps float64) int {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if eps < 0 {
+		panic("mat: negative tolerance")
+	}
+	s := rsvd.svd.s
+	var total float64
+	for _, v := range s {
+		total += v * v
+	}
+	bound := eps * eps * total
+	// Accumulate the tail from the smallest singular value up.
+	var tail float64
+	k := len(s)
+	for k > 1 {
+		v := s[k-1]
+		if tail+v*v > bound {
+			break
+		}
+		tail += v * v
+		k--
+	}
+	return k
+}
+
+// TruncateTo discards all but the leading k singular values and vectors of
+// the factorization, so that it becomes a rank-k factorization.
+//
+// TruncateTo will panic if k is not in [1, Rank()] or if the receiver does not
+// contain a successful factorization.
+func (rsvd *RSVD) TruncateTo(k int) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if k < 1 || rsvd.rank < k {
+		panic(ErrShape)
+	}
+	rsvd.truncate(k)
+}
+
+// Reorder permutes the singular triplets of the factorization so that the
+// k'th singular value and the k'th columns of U and V are those that were at
+// position perm[k], for example to match the triplets against a reference
+// basis. The eigenvalues of a symmetric factorization are permuted with them.
+// After Reorder the singular values are no longer in descending order, and
+// TruncateTo keeps the leading triplets in the new order.
+//
+// Reorder will panic if perm is not a permutation of 0, …, Rank()-1, or if the
+// receiver does not contain a successful factorization.
+func (rsvd *RSVD) Reorder(perm []int) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	k := rsvd.rank
+	if len(perm) != k {
+		panic(badPerm)
+	}
+	seen := make([]bool, k)
+	for _, p := range perm {
+		if p < 0 || k <= p || seen[p] {
+			panic(badPerm)
+		}
+		seen[p] = true
+	}
+
+	svd := rsvd.svd
+	svd.s = permuted(svd.s, perm)
+	if rsvd.eig != nil {
+		rsvd.eig = permuted(rsvd.eig, perm)
+	}
+	if rsvd.hasU() {
+		u := svd.u
+		for i := 0; i < u.Rows; i++ {
+			row := u.Data[i*u.Stride : i*u.Stride+u.Cols]
+			copy(row, permuted(row, perm))
+		}
+	}
+	if rsvd.hasV() {
+		vt := NewDense(k, svd.vt.Cols, nil)
+		for i, p := range perm {
+			copy(vt.rawRowView(i), svd.vt.Data[p*svd.vt.Stride:p*svd.vt.Stride+svd.vt.Cols])
+		}
+		svd.vt = vt.mat
+	}
+}
+
+// permuted returns a newly allocated slice whose i'th element is s[perm[i]].
+func permuted(s []float64, perm []int) []float64 {
+	dst := make([]float64, len(perm))
+	for i, p := range perm {
+		dst[i] = s[p]
+	}
+	return dst
+}
+
+// LowRankApprox places into dst the rank-k approximation U Σ Vᵀ of A computed
+// by the randomized singular value decomposition, using rnd as the source of
+// randomness for the projection. If rnd is nil, the global rand source is used.
+//
+// If dst is empty, LowRankApprox will resize dst to be m×n. When dst is
+// non-empty, LowRankApprox will panic if dst is not m×n. LowRankApprox panics
+// under the same conditions as RSVD.Factorize and returns ErrFailedSVD if the
+// factorization fails.
+func LowRankApprox(dst *Dense, A Matrix, rank int, rnd *rand.Rand) error {
+	var rsvd RSVD
+	if !rsvd.Factorize(A, rank, withRand(rnd)) {
+		return ErrFailedSVD
+	}
+	dst.reuseAsNonZeroed(A.Dims())
+	rsvd.approxTo(dst)
+	return nil
+}
+
+// BestRankKError returns the Frobenius norm error of the best rank-k
+// approximation of A,
+//  sqrt(σ_{k+1}² + ... + σ_min(m,n)²),
+// which is the smallest error achievable by any rank-k approximation and so
+// gives a reference for the error of the randomized decomposition.
+//
+// BestRankKError computes the singular values of A with a deterministic SVD,
+// which takes O(min(m,n)·m·n) time, so it is only intended for validation at
+// moderate sizes. BestRankKError will panic if k is negative, or with
+// ErrFailedSVD if the singular value decomposition fails.
+func BestRankKError(A Matrix, k int) float64 {
+	if k < 0 {
+		panic(ErrShape)
+	}
+	var svd SVD
+	if !svd.Factorize(A, SVDNone) {
+		panic(ErrFailedSVD)
+	}
+	s := svd.Values(nil)
+	if k >= len(s) {
+		return 0
+	}
+	// Accumulate from the smallest singular value up
+	// to avoid losing the tail to the largest terms.
+	var ss float64
+	for i := len(s) - 1; i >= k; i-- {
+		ss += s[i] * s[i]
+	}
+	return math.Sqrt(ss)
+}
+
+// BestRankOne returns an approximation of the leading singular triplet of A,
+// so that sigma u vᵀ approximates the best rank-one approximation of A. It is
+// the building block of greedy low-rank fitting, where the triplet of the
+// residual is subtracted at each step.
+//
+// The triplet is computed with iters steps of the power iteration
+//  u = A v / ‖A v‖, v = Aᵀ u / ‖Aᵀ u‖,
+// which is the power method on AᵀA, starting from a Gaussian vector drawn from
+// rnd. A is only accessed through two matrix-vector products per step. The
+// singular vectors converge at the rate (σ₂/σ₁)^(2·iters), and sigma is the
+// norm of the last product, which is a lower bound of σ₁. The returned u and v
+// are unit vectors with the sign chosen so that the element of v with the
+// largest magnitude is positive, so that the direction does not depend on the
+// start vector once the iteration has converged. If A is zero, sigma is zero
+// and u and v are the first standard basis vectors.
+//
+// If rnd is nil, the global rand source is used. BestRankOne will panic if
+// iters is less than one or if A has zero size.
+func BestRankOne(A Matrix, iters int, rnd *rand.Rand) (sigma float64, u, v []float64) {
+	if iters < 1 {
+		panic("mat: number of iterations must be positive")
+	}
+	m, n := A.Dims()
+	if m == 0 || n == 0 {
+		panic(ErrShape)
+	}
+	normFloat64 := rand.NormFloat64
+	if rnd != nil {
+		normFloat64 = rnd.NormFloat64
+	}
+
+	x := NewVecDense(n, nil)
+	for i := range x.mat.Data {
+		x.mat.Data[i] = normFloat64()
+	}
+	x.ScaleVec(1/Norm(x, 2), x)
+	y := NewVecDense(m, nil)
+	for it := 0; it < iters; it++ {
+		y.MulVec(A, x)
+		sigma = Norm(y, 2)
+		if sigma == 0 {
+			break
+		}
+		y.ScaleVec(1/sigma, y)
+		x.MulVec(A.T(), y)
+		sigma = Norm(x, 2)
+		if sigma == 0 {
+			break
+		}
+		x.ScaleVec(1/sigma, x)
+	}
+	u, v = y.mat.Data, x.mat.Data
+	if sigma == 0 {
+		for i := range u {
+			u[i] = 0
+		}
+		for i := range v {
+			v[i] = 0
+		}
+		u[0], v[0] = 1, 1
+		return 0, u, v
+	}
+	if v[blas64.Iamax(x.mat)] < 0 {
+		floats.Scale(-1, u)
+		floats.Scale(-1, v)
+	}
+	return sigma, u, v
+}
+
+// rankSweepOversample is the number of sketch columns used by RankSweep in
+// addition to maxRank.
+const rankSweepOversample = 10
+
+// RankSweep returns the estimated relative Frobenius norm errors
+//  ‖A - A_k‖_F / ‖A‖_F
+// of the randomized rank-k approximations A_k of A at the ranks
+// k = step, 2·step, … not exceeding maxRank. A single randomized
+// factorization is computed with rnd as the source of randomness from a
+// sketch with maxRank+10 columns, at most min(m,n), of which the leading
+// maxRank singular values are kept, and the error at each rank is estimated
+// from the energy of A not captured by the leading k singular values,
+//  ‖A - A_k‖_F² ≈ ‖A‖_F² - (σ_1² + ... + σ_k²),
+// which is exact for the rank-k truncation of the projection of A onto the
+// sketched range. The errors are therefore randomized estimates that depend
+// on rnd, and the oversampling keeps the bias of the sketch small up to
+// maxRank. If A is zero, all the errors are zero.
+//
+// RankSweep will panic if step is less than one, if maxRank is not in
+// [step, min(m,n)], or with ErrFailedSVD if the factorization fails.
+func RankSweep(A Matrix, maxRank, step int, rnd *rand.Rand) []float64 {
+	if step < 1 {
+		panic("mat: rank step must be positive")
+	}
+	m, n := A.Dims()
+	if maxRank < step || min(m, n) < maxRank {
+		panic(ErrShape)
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(A, maxRank, withRand(rnd), RSVDOversample(rankSweepOversample)) {
+		panic(ErrFailedSVD)
+	}
+	s := rsvd.Values(nil)
+
+	norm := Norm(A, 2)
+	errs := make([]float64, maxRank/step)
+	if norm == 0 {
+		return errs
+	}
+	total := norm * norm
+	var captured float64
+	for i, v := range s {
+		captured += v * v
+		if (i+1)%step == 0 {
+			errs[i/step] = math.Sqrt(math.Max(total-captured, 0)) / norm
+		}
+	}
+	return errs
+}
+
+// CompareSpectra returns the maximum relative error of the singular values of
+// the randomized decomposition rsvd of A,
+//  max_i |σ̃_i - σ_i| / σ_i,
+// where σ_i are the leading singular values of A computed by a deterministic
+// SVD. Errors for zero singular values of A are taken relative to the largest
+// singular value instead, and CompareSpectra returns zero if A is zero.
+//
+// CompareSpectra computes the full set of singular values of A, which takes
+// O(min(m,n)·m·n) time, so it is only intended for test-sized matrices.
+// CompareSpectra will panic if rsvd does not contain a successful
+// factorization, if A does not have the dimensions of the factorized
+// matrix, or with ErrFailedSVD if the deterministic SVD fails.
+func CompareSpectra(rsvd *RSVD, A Matrix) (maxRelErr float64) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	m, n := A.Dims()
+	if m != rsvd.m || n != rsvd.n {
+		panic(ErrShape)
+	}
+	var svd SVD
+	if !svd.Factorize(A, SVDNone) {
+		panic(ErrFailedSVD)
+	}
+	want := svd.Values(nil)
+	if want[0] == 0 {
+		return 0
+	}
+	for i, s := range rsvd.Values(nil) {
+		ref := want[i]
+		if ref == 0 {
+			ref = want[0]
+		}
+		maxRelErr = math.Max(maxRelErr, math.Abs(s-want[i])/ref)
+	}
+	return maxRelErr
+}
+
+// SubspaceAngle returns the principal angles between the column spaces of the
+// left singular vectors of the decompositions a and b in ascending order. The
+// cosines of the angles are the singular values of Uaᵀ Ub, so there are
+// min(rank_a, rank_b) angles in [0, π/2]. An angle of zero indicates a
+// direction common to both subspaces, so the angles quantify how much the
+// captured subspace changed between two factorizations.
+//
+// SubspaceAngle will panic if a or b does not contain a successful
+// factorization, if the factorized matrices do not have the same number of
+// rows, or with ErrFailedSVD if the singular value decomposition of Uaᵀ Ub
+// fails.
+func SubspaceAngle(a, b *RSVD) []float64 {
+	if !a.succFact() || !b.succFact() {
+		panic(badFact)
+	}
+	if a.m != b.m {
+		panic(ErrShape)
+	}
+	var ua, ub, c Dense
+	a.UTo(&ua)
+	b.UTo(&ub)
+	c.Mul(ua.T(), &ub)
+	var svd SVD
+	if !svd.Factorize(&c, SVDNone) {
+		panic(ErrFailedSVD)
+	}
+	angles := svd.Values(nil)
+	for i, s := range angles {
+		// Rounding may give cosines slightly above one.
+		angles[i] = math.Acos(math.Min(s, 1))
+	}
+	return angles
+}
 
-	// Truncate QFull:
-	// [Q] = m × rank
-	Q := QFull.Slice(0, m, 0, rank).(*Dense)
+// factorizeSym stores in the receiver the decomposition of the symmetric
+// matrix A ≈ Q T Qᵀ obtained from the eigendecomposition T = W Λ Wᵀ, where
+// Q is rsvd.q, or the identity if rsvd.q is nil. The inner SVD holds
+// Uy = W and Vyᵀ = sign(Λ) Wᵀ Qᵀ with the eigenpairs ordered by decreasing
+// magnitude of the eigenvalues. If vectors is false, only the eigenvalues
+// and singular values are stored.
+func (rsvd *RSVD) factorizeSym(t Symmetric, vectors bool) bool {
+	var eig EigenSym
+	if !eig.Factorize(t, vectors) {
+		return false
+	}
+	k := t.Symmetric()
+	lambda := eig.Values(nil)
+	if !vectors {
+		rsvd.eig = lambda
+		sort.SliceStable(rsvd.eig, func(i, j int) bool {
+			return math.Abs(rsvd.eig[i]) > math.Abs(rsvd.eig[j])
+		})
+		s := make([]float64, k)
+		for i, l := range rsvd.eig {
+			s[i] = math.Abs(l)
+		}
+		*rsvd.svd = SVD{kind: SVDNone, s: s}
+		return true
+	}
+	var w Dense
+	eig.VectorsTo(&w)
 
-	// Project M into Q:
-	// [Y] = [Qᵀ × M] = (rank × m) × (m × n) = rank × n
-	Y := NewDense(rank, n, nil)
-	Y.Mul(Q.T(), A)
+	perm := make([]int, k)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		return math.Abs(lambda[perm[i]]) > math.Abs(lambda[perm[j]])
+	})
+	u := NewDense(k, k, nil)
+	sw := NewDense(k, k, nil)
+	rsvd.eig = make([]float64, k)
+	s := make([]float64, k)
+	for j, pj := range perm {
+		rsvd.eig[j] = lambda[pj]
+		s[j] = math.Abs(lambda[pj])
+		sign := 1.0
+		if lambda[pj] < 0 {
+			sign = -1
+		}
+		for i := 0; i < k; i++ {
+			u.set(i, j, w.at(i, pj))
+			sw.set(i, j, sign*w.at(i, pj))
+		}
+	}
+	var vt Dense
+	if rsvd.q == nil {
+		vt.CloneFrom(sw.T())
+	} else {
+		vt.Mul(sw.T(), rsvd.q.T())
+	}
+	*rsvd.svd = SVD{
+		kind: SVDThin,
+		s:    s,
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+	return true
+}
 
-	rsvd.m = m
-	rsvd.q = Q
+// Eigenvalues returns the signed eigenvalues of a symmetric factorized matrix,
+// ordered to correspond to the singular values returned by Values.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length rank, and Eigenvalues
+// will panic with ErrSliceLengthMismatch otherwise. If the input slice is nil,
+// a new slice of the appropriate length will be allocated and returned.
+//
+// Eigenvalues will panic if the receiver does not contain a successful
+// factorization of a Symmetric matrix.
+func (rsvd *RSVD) Eigenvalues(dst []float64) []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if rsvd.eig == nil {
+		panic("mat: eigenvalues not computed")
+	}
+	if dst == nil {
+		dst = make([]float64, len(rsvd.eig))
+	}
+	if len(dst) != len(rsvd.eig) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, rsvd.eig)
+	return dst
+}
 
-	// Perform SVD for Y:
-	// [Y] = [Uy × Σ × V] = (rank × rank) × (rank × rank) × (rank × n) = rank × n
-	return rsvd.svd.Factorize(Y, SVDThin)
+// factorizeFull computes the deterministic thin SVD of A, with columns scaled
+// by rsvd.scale if it is not nil, and stores it in the receiver.
+func (rsvd *RSVD) factorizeFull(A Matrix, kind SVDKind) bool {
+	m, n := A.Dims()
+	if rsvd.scale != nil {
+		d := DenseCopyOf(A)
+		for i := 0; i < m; i++ {
+			row := d.rawRowView(i)
+			for j, s := range rsvd.scale {
+				row[j] /= s
+			}
+		}
+		A = d
+	}
+	rsvd.m, rsvd.n = m, n
+	rsvd.rank = min(m, n)
+	rsvd.q = nil
+	return rsvd.svd.Factorize(A, kind)
 }
 
 // Values returns the singular values of the factorized matrix in descending order.
@@ -79,18 +1799,30 @@ func (rsvd *RSVD) Factorize(A Matrix, rank int) bool {
 //
 // Values will panic if the receiver does not contain a successful factorization.
 func (rsvd *RSVD) Values(s []float64) []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
 	return rsvd.svd.Values(s)
 }
 
-// UTo extracts the matrix U from the singular value decomposition..
+// UTo extracts the matrix U from the randomized singular value decomposition.
 //
 // If dst is empty, UTo will resize dst to be m×rank. When dst is non-empty, then
 // UTo will panic if dst is not the appropriate size. UTo will also panic if
 // the receiver does not contain a successful factorization, or if U was
 // not computed during factorization.
 func (rsvd *RSVD) UTo(dst *Dense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasU() {
+		panic(noU)
+	}
+	if rsvd.q == nil {
+		rsvd.svd.UTo(dst)
+		return
+	}
 	var Uy Dense
-	// Uy := NewDense(rsvd.rank, rsvd.rank, nil)
 	rsvd.svd.UTo(&Uy)
 
 	// Project Uy into QS:
@@ -110,24 +1842,955 @@ func (rsvd *RSVD) UTo(dst *Dense) {
 	dst.Copy(U)
 }
 
-// VTo extracts the matrix V from the randomized singular value decomposition
+// VTo extracts the matrix V from the randomized singular value decomposition.
 //
 // If dst is empty, VTo will resize dst to be n×rank. When dst is non-empty, then
 // VTo will panic if dst is not the appropriate size. VTo will also panic if
 // the receiver does not contain a successful factorization, or if V was
 // not computed during factorization.
+//
+// If the factorization was computed with RSVDStandardize, the rows of V are
+// scaled by the column standard deviations of A.
 func (rsvd *RSVD) VTo(dst *Dense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasV() {
+		panic(noV)
+	}
 	rsvd.svd.VTo(dst)
+	for i, s := range rsvd.scale {
+		row := dst.rawRowView(i)
+		for j := range row {
+			row[j] *= s
+		}
+	}
+}
+
+// Factors returns newly allocated matrices holding the factors of the
+// decomposition A ≈ U Σ Vᵀ, where u is m×rank, sigma is the rank×rank
+// diagonal matrix of singular values in descending order and v is n×rank.
+// The factors are those returned by UTo, Values and VTo.
+//
+// Factors will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) Factors() (u, sigma, v *Dense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	u, v = &Dense{}, &Dense{}
+	rsvd.UTo(u)
+	rsvd.VTo(v)
+	sigma = NewDense(rsvd.rank, rsvd.rank, nil)
+	for i, s := range rsvd.Values(nil) {
+		sigma.set(i, i, s)
+	}
+	return u, sigma, v
+}
+
+// SigmaTo places the rank×rank diagonal matrix Σ of singular values in
+// descending order into dst.
+//
+// If dst is empty, SigmaTo will resize dst to be rank×rank. When dst is
+// non-empty, SigmaTo will panic if dst is not rank×rank. SigmaTo will also
+// panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) SigmaTo(dst *DiagDense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(rsvd.rank)
+	for i, s := range rsvd.svd.s {
+		dst.mat.Data[i*dst.mat.Inc] = s
+	}
+}
+
+// ApproxTo places the rank-rank approximation U Σ Vᵀ of the factorized
+// matrix into dst.
+//
+// If dst is empty, ApproxTo will resize dst to be m×n. When dst is non-empty,
+// ApproxTo will panic if dst is not m×n. ApproxTo will also panic if either
+// set of singular vectors was not computed, or if the receiver does not
+// contain a successful factorization.
+func (rsvd *RSVD) ApproxTo(dst *Dense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	dst.reuseAsNonZeroed(rsvd.m, rsvd.n)
+	rsvd.approxTo(dst)
+}
+
+// Orthonormalize replaces the factors of the decomposition with equivalent
+// factors whose singular vectors are orthonormal to working precision. Lifting
+// the singular vectors of the projected matrix through the sketch basis,
+// U = Q Uy, can leave the columns of U slightly off orthonormal. Orthonormalize
+// computes the thin QR factorizations U = Q_u R_u and V = Q_v R_v and the SVD
+// of the rank×rank core
+//  R_u Σ R_vᵀ = W Σ' Zᵀ,
+// and stores U = Q_u W, Σ = Σ' and V = Q_v Z, which leaves the product U Σ Vᵀ
+// unchanged while absorbing any residual scaling into the singular values.
+// The factors are stored explicitly afterwards, so no sketch basis is
+// retained. If the columns of A were standardized, the orthonormalized V is
+// that of the standardized matrix, so the rows of V returned by VTo remain
+// scaled by the column standard deviations of A.
+//
+// Orthonormalize will panic if the receiver does not contain a successful
+// factorization, or with ErrFailedSVD if the SVD of the core fails.
+func (rsvd *RSVD) Orthonormalize() {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasV() {
+		panic(noV)
+	}
+	var u Dense
+	rsvd.UTo(&u)
+	vt := rsvd.svd.vt
+	v := DenseCopyOf((&Dense{mat: vt, capRows: vt.Rows, capCols: vt.Cols}).T())
+	qu, ru := thinQR(&u)
+	qv, rv := thinQR(v)
+
+	var core Dense
+	core.ScaleCols(rsvd.svd.s, ru)
+	core.Mul(&core, rv.T())
+	var svd SVD
+	if !svd.Factorize(&core, SVDThin) {
+		panic(ErrFailedSVD)
+	}
+	var w, z Dense
+	svd.UTo(&w)
+	svd.VTo(&z)
+	var newU, newV, newVt Dense
+	newU.Mul(qu, &w)
+	newV.Mul(qv, &z)
+	newVt.CloneFrom(newV.T())
+
+	rsvd.q = nil
+	*rsvd.svd = SVD{
+		kind: SVDThin,
+		s:    svd.Values(nil),
+		u:    newU.mat,
+		vt:   newVt.mat,
+	}
+	if rsvd.eig != nil {
+		// For a symmetric input V is U with the columns of the
+		// negative eigenvalues negated, which recovers the signs.
+		for j, s := range rsvd.svd.s {
+			rsvd.eig[j] = s
+			if Dot(newU.ColView(j), newV.ColView(j)) < 0 {
+				rsvd.eig[j] = -s
+			}
+		}
+	}
+}
+
+// Coherence returns the coherence of the subspace spanned by the columns of
+// the m×rank matrix U of left singular vectors,
+//  μ = (m/rank) max_i ‖U[i,:]‖²,
+// which ranges from one, for a subspace spread evenly over all coordinates, to
+// m/rank, for a subspace containing a standard basis vector. Low coherence is
+// the usual requirement of matrix completion and compressed sensing guarantees.
+//
+// Coherence will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) Coherence() float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	var u Dense
+	rsvd.UTo(&u)
+	var mx float64
+	for i := 0; i < rsvd.m; i++ {
+		var ss float64
+		for _, v := range u.rawRowView(i) {
+			ss += v * v
+		}
+		mx = math.Max(mx, ss)
+	}
+	return float64(rsvd.m) / float64(rsvd.rank) * mx
+}
+
+// RowImportances returns a newly allocated slice holding the Euclidean norms
+// of the rows of U Σ. Since V has orthonormal columns, these are the norms of
+// the rows of the approximation U Σ Vᵀ of A, and their squares sum to the
+// energy ‖U Σ Vᵀ‖²_F captured by the factorization. They can be used to rank
+// the rows of A or as sampling probabilities for a CUR decomposition. If the
+// factorization was computed with RSVDStandardize, U and Σ are those of the
+// scaled matrix, so the norms are those of the rows of its approximation
+// rather than of the approximation of A.
+//
+// RowImportances will panic if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) RowImportances() []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	var u Dense
+	rsvd.UTo(&u)
+	return scaledRowNorms(&u, rsvd.svd.s)
+}
+
+// ColumnImportances returns a newly allocated slice holding the Euclidean
+// norms of the rows of V Σ, which are the norms of the columns of the
+// approximation U Σ Vᵀ of A. See RowImportances for their interpretation.
+//
+// ColumnImportances will panic if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) ColumnImportances() []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	var v Dense
+	rsvd.VTo(&v)
+	return scaledRowNorms(&v, rsvd.svd.s)
+}
+
+// scaledRowNorms returns the Euclidean norms of the rows of a Σ, where Σ is
+// the diagonal matrix of the singular values s.
+func scaledRowNorms(a *Dense, s []float64) []float64 {
+	r, _ := a.Dims()
+	norms := make([]float64, r)
+	for i := range norms {
+		var ss float64
+		for j, v := range a.rawRowView(i) {
+			v *= s[j]
+			ss += v * v
+		}
+		norms[i] = math.Sqrt(ss)
+	}
+	return norms
+}
+
+// WasRandomized returns whether the factors were computed from a randomized
+// sketch of A. It returns false if Factorize fell back to the deterministic
+// SVD because the rank was at least min(m,n), or if the sketch showed that A
+// is zero, in which case the factors do not depend on the random projection.
+//
+// WasRandomized will panic if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) WasRandomized() bool {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	return rsvd.randomized
+}
+
+// Stats returns diagnostics of the factorization.
+//
+// Stats will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) Stats() RSVDStats {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	return rsvd.stats
+}
+
+// RowView returns a newly allocated slice holding row i of the approximation
+// U Σ Vᵀ of A. Only the requested row is computed, in O(rank·(rank+n)) time,
+// so rows of approximations that are too large to be formed can be inspected.
+//
+// RowView will panic if the receiver does not contain a successful
+// factorization, or with ErrRowAccess if i is out of range.
+func (rsvd *RSVD) RowView(i int) []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasU() {
+		panic(noU)
+	}
+	if !rsvd.hasV() {
+		panic(noV)
+	}
+	if i < 0 || rsvd.m <= i {
+		panic(ErrRowAccess)
+	}
+	// Form row i of U scaled by Σ, uᵢ Σ.
+	u := rsvd.svd.u
+	k := rsvd.rank
+	us := make([]float64, k)
+	if rsvd.q == nil {
+		copy(us, u.Data[i*u.Stride:i*u.Stride+k])
+	} else {
+		blas64.Gemv(blas.Trans, 1, blas64.General{Rows: u.Rows, Cols: k, Stride: u.Stride, Data: u.Data},
+			blas64.Vector{N: u.Rows, Inc: 1, Data: rsvd.q.rawRowView(i)}, 0, blas64.Vector{N: k, Inc: 1, Data: us})
+	}
+	for j, s := range rsvd.svd.s {
+		us[j] *= s
+	}
+
+	// Form uᵢ Σ Vᵀ.
+	vt := rsvd.svd.vt
+	row := make([]float64, vt.Cols)
+	blas64.Gemv(blas.Trans, 1, blas64.General{Rows: k, Cols: vt.Cols, Stride: vt.Stride, Data: vt.Data},
+		blas64.Vector{N: k, Inc: 1, Data: us}, 0, blas64.Vector{N: vt.Cols, Inc: 1, Data: row})
+	for j, s := range rsvd.scale {
+		row[j] *= s
+	}
+	return row
+}
+
+// ColView returns a newly allocated slice holding column j of the
+// approximation U Σ Vᵀ of A. Only the requested column is computed, in
+// O(rank·(rank+m)) time, so columns of approximations that are too large to
+// be formed can be inspected.
+//
+// ColView will panic if the receiver does not contain a successful
+// factorization, or with ErrColAccess if j is out of range.
+func (rsvd *RSVD) ColView(j int) []float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasU() {
+		panic(noU)
+	}
+	if !rsvd.hasV() {
+		panic(noV)
+	}
+	vt := rsvd.svd.vt
+	if j < 0 || vt.Cols <= j {
+		panic(ErrColAccess)
+	}
+	// Form Σ vⱼ from column j of Vᵀ.
+	k := rsvd.rank
+	sv := make([]float64, k)
+	for i, s := range rsvd.svd.s {
+		sv[i] = s * vt.Data[i*vt.Stride+j]
+	}
+	if rsvd.scale != nil {
+		for i := range sv {
+			sv[i] *= rsvd.scale[j]
+		}
+	}
+
+	// Form U Σ vⱼ, lifting through Q if the factors are projected.
+	u := rsvd.svd.u
+	uy := make([]float64, u.Rows)
+	blas64.Gemv(blas.NoTrans, 1, blas64.General{Rows: u.Rows, Cols: k, Stride: u.Stride, Data: u.Data},
+		blas64.Vector{N: k, Inc: 1, Data: sv}, 0, blas64.Vector{N: u.Rows, Inc: 1, Data: uy})
+	if rsvd.q == nil {
+		return uy
+	}
+	col := make([]float64, rsvd.m)
+	blas64.Gemv(blas.NoTrans, 1, rsvd.q.mat, blas64.Vector{N: u.Rows, Inc: 1, Data: uy}, 0, blas64.Vector{N: rsvd.m, Inc: 1, Data: col})
+	return col
+}
+
+// Projector returns the n×rank matrix P that maps the rows of A into the
+// space spanned by the leading right singular vectors, so that X·P gives the
+// coordinates of the rows of X in the reduced space and A·P ≈ U Σ.
+//
+// Without standardization P is V. If the factorization was computed with
+// RSVDStandardize, the rows of the orthonormal right singular vectors of the
+// scaled matrix are divided by the column standard deviations of A, so that
+// X·P projects the standardized rows of X.
+//
+// Projector will panic if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) Projector() *Dense {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !rsvd.hasV() {
+		panic(noV)
+	}
+	var p Dense
+	rsvd.svd.VTo(&p)
+	for i, s := range rsvd.scale {
+		row := p.rawRowView(i)
+		for j := range row {
+			row[j] /= s
+		}
+	}
+	return &p
+}
+
+// PInvTo computes the n×m Moore–Penrose pseudoinverse of the approximation
+// U Σ Vᵀ of A,
+//  V Σ⁺ Uᵀ,
+// placing the result in dst, where Σ⁺ holds the reciprocals of the non-zero
+// singular values. Singular values set to zero by RSVDSVTol are excluded.
+//
+// If dst is empty, PInvTo will resize dst to be n×m. When dst is non-empty,
+// PInvTo will panic if dst is not n×m. PInvTo will also panic if the receiver
+// does not contain a successful factorization.
+func (rsvd *RSVD) PInvTo(dst *Dense) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	v.ScaleCols(rsvd.pinvValues(), &v)
+	dst.Mul(&v, u.T())
 }
 
-// makeRandomMatrix creates random matrix with given amount of rows and cols
-func makeRandomMatrix(rows, columns int) *Dense {
-	dataLength := rows * columns
-	data := make([]float64, dataLength, dataLength)
+// SolveTo computes the minimum norm least squares solution of
+//  U Σ Vᵀ X = B,
+// X = V Σ⁺ Uᵀ B, for the approximation of A, placing the result in dst.
+// Singular values set to zero by RSVDSVTol are excluded.
+//
+// SolveTo will panic if b does not have m rows, if dst is non-empty and not
+// n×c where c is the number of columns of b, or if the receiver does not
+// contain a successful factorization.
+func (rsvd *RSVD) SolveTo(dst *Dense, b Matrix) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if br, _ := b.Dims(); br != rsvd.m {
+		panic(ErrShape)
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	var ub Dense
+	ub.Mul(u.T(), b)
+	ub.ScaleRows(rsvd.pinvValues(), &ub)
+	dst.Mul(&v, &ub)
+}
+
+// ApplyInverseTo computes
+//  (Â + μI)⁻¹ b,
+// where Â = U Σ Vᵀ is the approximation of the square matrix A, and places the
+// result in dst. The inverse is applied with the Woodbury identity
+//  (μI + U Σ Vᵀ)⁻¹ = (I - U Σ (μI + Vᵀ U Σ)⁻¹ Vᵀ) / μ,
+// which only requires the solution of a rank×rank system, so the receiver can
+// be used as a preconditioner for iterative solvers of systems with A at a cost
+// of O(n·rank·c) for an n×c b. The regularizer μ is set by RSVDRegularizer, and
+// is otherwise the smallest retained singular value.
+//
+// ApplyInverseTo will panic if A is not square, if b does not have n rows, if
+// dst is non-empty and not n×c, or if the receiver does not contain a
+// successful factorization. It will panic with ErrSingular if the default
+// regularizer is zero or the rank×rank system is singular.
+func (rsvd *RSVD) ApplyInverseTo(dst *Dense, b Matrix) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	n := rsvd.m
+	if rsvd.n != n {
+		panic(ErrSquare)
+	}
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	mu := rsvd.mu
+	if mu == 0 {
+		mu = rsvd.svd.s[rsvd.rank-1]
+		if mu == 0 {
+			panic(ErrSingular)
+		}
+	}
+
+	var us, v Dense
+	rsvd.UTo(&us)
+	rsvd.VTo(&v)
+	us.ScaleCols(rsvd.svd.s, &us)
+	var core Dense
+	core.Mul(v.T(), &us)
+	for i := 0; i < rsvd.rank; i++ {
+		core.set(i, i, core.at(i, i)+mu)
+	}
+	var vtb, w Dense
+	vtb.Mul(v.T(), b)
+	if err := w.Solve(&core, &vtb); err != nil {
+		if c, ok := err.(Condition); !ok || math.IsInf(float64(c), 1) {
+			panic(ErrSingular)
+		}
+	}
+	var corr Dense
+	corr.Mul(&us, &w)
+	dst.reuseAsNonZeroed(n, bc)
+	dst.Sub(b, &corr)
+	dst.Scale(1/mu, dst)
+}
+
+// TheoreticalErrorBound returns the a priori bound on the spectral norm range
+// error ‖(I - Q Qᵀ) A‖_2 of the randomized sketch that holds with probability
+// at least 1 - failureProb, from the deviation bound of Halko, Martinsson and
+// Tropp (Theorem 10.8). For a sketch of rank k with oversampling p >= 4, the
+// bound is
+//  [1 + t√(3k/(p+1)) + t·e√(k+p)/(p+1)·√(min(m,n)-k) + u·t·e√(k+p)/(p+1)] σ_{k+1}
+// with t = (4/failureProb)^(1/p) and u = √(2 log(2/failureProb)), where the
+// energy of the tail singular values of A is bounded by (min(m,n)-k) σ_{k+1}².
+// With q power iterations the factor in brackets is raised to the power
+// 1/(2q+1) (Corollary 10.10). The bound is useful for choosing the
+// oversampling to meet a confidence level, since the factor decreases rapidly
+// with p.
+//
+// The theory assumes a Gaussian test matrix, as used with RSVDOrthoProjection;
+// for the default uniform test matrix the bound is a heuristic. σ_{k+1} is not
+// known, and is estimated by the largest singular value of the sketch that was
+// discarded by the truncation to the rank k, which does not exceed σ_{k+1}, so
+// the returned value is itself an estimate. The bound refers to the rank and
+// oversampling used by Factorize, regardless of later calls to TruncateTo.
+//
+// TheoreticalErrorBound returns +Inf if the oversampling is less than four,
+// where the bound does not apply, and zero if the factors were not computed
+// from a randomized sketch. It will panic if failureProb is not in (0, 1) or
+// if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) TheoreticalErrorBound(failureProb float64) float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if !(0 < failureProb && failureProb < 1) {
+		panic("mat: failure probability out of range")
+	}
+	if !rsvd.randomized {
+		return 0
+	}
+	k, p := float64(rsvd.target), float64(rsvd.oversample)
+	if p < 4 {
+		return math.Inf(1)
+	}
+	t := math.Max(1, math.Pow(4/failureProb, 1/p))
+	u := math.Max(1, math.Sqrt(2*math.Log(2/failureProb)))
+	c := math.E * math.Sqrt(k+p) / (p + 1)
+	tail := math.Sqrt(float64(min(rsvd.m, rsvd.n) - rsvd.target))
+	factor := 1 + t*math.Sqrt(3*k/(p+1)) + t*c*tail + u*t*c
+	if q := rsvd.stats.PowerIterations; q > 0 {
+		factor = math.Pow(factor, 1/float64(2*q+1))
+	}
+	return factor * rsvd.next
+}
+
+// pinvValues returns the diagonal of Σ⁺.
+func (rsvd *RSVD) pinvValues() []float64 {
+	inv := make([]float64, len(rsvd.svd.s))
+	for i, s := range rsvd.svd.s {
+		if s != 0 {
+			inv[i] = 1 / s
+		}
+	}
+	return inv
+}
+
+// ResidualTo places the residual A - U Σ Vᵀ of the approximation of A into
+// dst. A must be the matrix that was factorized. Inspecting the spectrum of
+// the residual shows which directions are poorly captured by the factors.
+//
+// If dst is empty, ResidualTo will resize dst to be m×n. When dst is
+// non-empty, ResidualTo will panic if dst is not m×n. ResidualTo will also
+// panic if A is not m×n or if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) ResidualTo(dst *Dense, A Matrix) {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	m, n := A.Dims()
+	if m != rsvd.m || n != rsvd.n {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(m, n)
+	rsvd.approxTo(dst)
+	dst.Sub(A, dst)
+}
+
+// DeflatedOp returns a LinearOp for the residual A - U Σ Vᵀ of the
+// approximation of A, whose products are computed as A x - U (Σ Vᵀ x) and
+// Aᵀ x - V (Σ Uᵀ x) without forming the residual. A must be the matrix that
+// was factorized. Factorizing the returned operator with FactorizeOp captures
+// the next block of the spectrum of A, which allows a decomposition to be
+// extended by deflation. The operator holds copies of the factors, so it is
+// not affected by later changes to the receiver, and it retains A without
+// copying it.
+//
+// DeflatedOp will panic if A is not m×n, if either set of singular vectors was
+// not computed, or if the receiver does not contain a successful
+// factorization.
+func (rsvd *RSVD) DeflatedOp(A Matrix) LinearOp {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	m, n := A.Dims()
+	if m != rsvd.m || n != rsvd.n {
+		panic(ErrShape)
+	}
+	u, v := &Dense{}, &Dense{}
+	rsvd.UTo(u)
+	rsvd.VTo(v)
+	for i := 0; i < n; i++ {
+		row := v.rawRowView(i)
+		for j, s := range rsvd.svd.s {
+			row[j] *= s
+		}
+	}
+	return deflatedOp{a: A, u: u, v: v}
+}
+
+// CheckApprox returns nil if the relative Frobenius norm error
+//  ‖A - U Σ Vᵀ‖_F / ‖A‖_F
+// of the approximation of A is at most relTol, and an *ApproxError holding
+// the error and relTol otherwise. A must be the matrix that was factorized.
+// The error is computed exactly from the residual, which takes O(m·n·rank)
+// time. If A is zero, the relative error is zero if the approximation is zero
+// and infinite otherwise.
+//
+// CheckApprox will panic if relTol is negative, if A is not m×n or if the
+// receiver does not contain a successful factorization.
+func (rsvd *RSVD) CheckApprox(A Matrix, relTol float64) error {
+	if relTol < 0 {
+		panic("mat: negative tolerance")
+	}
+	var res Dense
+	rsvd.ResidualTo(&res, A)
+	num := Norm(&res, 2)
+	var relErr float64
+	if num != 0 {
+		relErr = num / Norm(A, 2)
+	}
+	if relErr <= relTol {
+		return nil
+	}
+	return &ApproxError{RelError: relErr, Tol: relTol}
+}
+
+// RangeError returns the Frobenius norm of the part of A outside the range
+// of the orthonormal sketch basis Q computed during factorization,
+//  ‖(I - Q Qᵀ) A‖_F,
+// which is the error of the approximation Q Qᵀ A of A before the inner SVD is
+// truncated to the requested rank. It is computed as ‖A - Q (Qᵀ A)‖_F with
+// two products with A. A must be the matrix that was factorized.
+//
+// If no sketch basis is retained, because the deterministic SVD was computed
+// or the transpose of A was factorized, the columns of U are used as the
+// basis instead.
+//
+// RangeError will panic if A is not m×n or if the receiver does not contain
+// a successful factorization.
+func (rsvd *RSVD) RangeError(A Matrix) float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	m, n := A.Dims()
+	if m != rsvd.m || n != rsvd.n {
+		panic(ErrShape)
+	}
+	q := rsvd.q
+	if q == nil {
+		q = &Dense{}
+		rsvd.UTo(q)
+	}
+	var qta, r Dense
+	qta.Mul(q.T(), A)
+	r.Mul(q, &qta)
+	r.Sub(A, &r)
+	return Norm(&r, 2)
+}
+
+// ErrEst returns a randomized a posteriori estimate of the spectral norm error
+//  ‖A - U Σ Vᵀ‖₂
+// of the approximation of A, computed from the images of probes standard
+// normal vectors ω under the residual as
+//  10 √(2/π) max_i ‖(A - U Σ Vᵀ) ω_i‖₂,
+// which bounds the error from above with probability at least 1 - 10^-probes
+// (Halko, Martinsson and Tropp, 2011, §4.3). A must be the matrix that was
+// factorized, and is only accessed through a single product with an
+// n×probes matrix, so the estimate is much cheaper than ResidualTo. A few
+// probes suffice in practice; ErrEst can be used to decide whether the
+// factorization should be repeated with a larger rank.
+//
+// If rnd is nil, the global rand source is used. ErrEst will panic if probes
+// is less than one, if A is not m×n, if either set of singular vectors was not
+// computed, or if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) ErrEst(A Matrix, probes int, rnd *rand.Rand) float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if probes < 1 {
+		panic("mat: number of probes must be positive")
+	}
+	m, n := A.Dims()
+	if m != rsvd.m || n != rsvd.n {
+		panic(ErrShape)
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+
+	omega := makeRandomMatrix(n, probes, distNormal, rnd)
+	var vto, r Dense
+	vto.Mul(v.T(), omega)
+	for i, s := range rsvd.svd.s {
+		row := vto.rawRowView(i)
+		for j := range row {
+			row[j] *= s
+		}
+	}
+	r.Mul(&u, &vto)
+	var ao Dense
+	ao.Mul(A, omega)
+	r.Sub(&ao, &r)
+
+	var maxNorm float64
+	for j := 0; j < probes; j++ {
+		maxNorm = math.Max(maxNorm, Norm(r.ColView(j), 2))
+	}
+	return 10 * math.Sqrt(2/math.Pi) * maxNorm
+}
+
+// approxTo places the approximation U Σ Vᵀ into dst, which must be m×n.
+func (rsvd *RSVD) approxTo(dst *Dense) {
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	s := rsvd.Values(nil)
+	for j, sv := range s {
+		for i := 0; i < u.mat.Rows; i++ {
+			u.set(i, j, u.at(i, j)*sv)
+		}
+	}
+	dst.Mul(&u, v.T())
+}
+
+// InnerSVD returns the singular value decomposition of the projected
+// rank×n matrix Y = Qᵀ A computed during factorization, Y = Uy Σ Vᵀ, where Q
+// is the m×rank orthonormal basis of the sketch of A, so that U = Q Uy. The
+// returned SVD has a rank×rank Uy and shares storage with the receiver; it
+// must not be modified, and it is invalidated by a subsequent call to
+// Factorize. If the columns of A were standardized, Y is the projection of the
+// standardized matrix.
+//
+// If the decomposition fell back to a deterministic SVD because rank was at
+// least min(m,n), there is no projection and InnerSVD returns the SVD of A
+// itself, with U of size m×min(m,n). If the transpose of A was factorized, as
+// chosen by RSVDAuto, InnerSVD returns the thin rank-k SVD of A with the
+// lifted singular vectors. For Symmetric inputs the inner SVD is
+// derived from the eigendecomposition of Qᵀ A Q and Vᵀ is lifted to n columns.
+//
+// InnerSVD will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) InnerSVD() *SVD {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	return rsvd.svd
+}
+
+// ToSVD returns an SVD holding the factors of the randomized decomposition so
+// that it can be used by code written against the SVD type. The returned SVD
+// is of kind SVDThin with an m×rank U, rank singular values and an n×rank V,
+// and does not share storage with the receiver.
+//
+// ToSVD will panic if the receiver does not contain a successful factorization.
+func (rsvd *RSVD) ToSVD() *SVD {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	var vt Dense
+	vt.CloneFrom(v.T())
+	return &SVD{
+		kind: SVDThin,
+		s:    rsvd.Values(nil),
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+}
+
+// QOrthonormalityError returns ‖QᵀQ - I‖_F for the orthonormal basis Q of
+// the approximate range of A computed during factorization. A large value
+// indicates that the basis lost orthogonality and that the factors may be
+// inaccurate.
+//
+// QOrthonormalityError will panic if the receiver does not contain a
+// successful factorization. If the factorization was computed without a
+// randomized sketch, or from the transpose of A as chosen by RSVDAuto, no
+// basis of the range of A is retained and QOrthonormalityError returns zero.
+func (rsvd *RSVD) QOrthonormalityError() float64 {
+	if !rsvd.succFact() {
+		panic(badFact)
+	}
+	if rsvd.q == nil {
+		// The deterministic decomposition has no sketch basis.
+		return 0
+	}
+	return orthoError(rsvd.q)
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (rsvd *RSVD) succFact() bool {
+	return rsvd.svd != nil && rsvd.svd.succFact()
+}
+
+// factorizeZero stores the rank-rank decomposition of an m×n zero matrix in
+// the receiver, with zero singular values and the leading columns of the
+// identity as singular vectors.
+func (rsvd *RSVD) factorizeZero(m, n, rank int) {
+	rsvd.randomized = false
+	rsvd.m, rsvd.n = m, n
+	rsvd.rank = rank
+	rsvd.q = NewDense(m, rank, nil)
+	for i := 0; i < rank; i++ {
+		rsvd.q.set(i, i, 1)
+	}
+	u := NewDense(rank, rank, nil)
+	vt := NewDense(rank, n, nil)
+	for i := 0; i < rank; i++ {
+		u.set(i, i, 1)
+		vt.set(i, i, 1)
+	}
+	*rsvd.svd = SVD{
+		kind: SVDThin,
+		s:    make([]float64, rank),
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+}
+
+// hasNaN returns whether any element of a is NaN.
+func hasNaN(a Matrix) bool {
+	r, c := a.Dims()
+	if d, ok := a.(*Dense); ok {
+		for i := 0; i < r; i++ {
+			for _, v := range d.rawRowView(i) {
+				if math.IsNaN(v) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if math.IsNaN(a.At(i, j)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isZeroDense returns whether all the elements of a are zero.
+func isZeroDense(a *Dense) bool {
+	for i := 0; i < a.mat.Rows; i++ {
+		for _, v := range a.rawRowView(i) {
+			if v != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// colStdDevs returns the sample standard deviations of the columns of a.
+// Columns with zero variance are given a standard deviation of one.
+func colStdDevs(a Matrix) []float64 {
+	r, c := a.Dims()
+	sd := make([]float64, c)
+	col := make([]float64, r)
+	for j := range sd {
+		Col(col, j, a)
+		var mean float64
+		for _, v := range col {
+			mean += v
+		}
+		mean /= float64(r)
+		var ss float64
+		for _, v := range col {
+			d := v - mean
+			ss += d * d
+		}
+		sd[j] = 1
+		if r > 1 && ss != 0 {
+			sd[j] = math.Sqrt(ss / float64(r-1))
+		}
+	}
+	return sd
+}
+
+// sketchMul places the product a·p into dst. When a is a band matrix the
+// product is formed one column at a time using banded matrix-vector products.
+func sketchMul(dst *Dense, a Matrix, p *Dense) {
+	b, t, ok := rawBandOf(a)
+	if !ok {
+		dst.Mul(a, p)
+		return
+	}
+	r, _ := a.Dims()
+	_, c := p.Dims()
+	dst.reuseAsNonZeroed(r, c)
+	for j := 0; j < c; j++ {
+		x := blas64.Vector{N: p.mat.Rows, Inc: p.mat.Stride, Data: p.mat.Data[j:]}
+		y := blas64.Vector{N: r, Inc: dst.mat.Stride, Data: dst.mat.Data[j:]}
+		blas64.Gbmv(t, 1, b, x, 0, y)
+	}
+}
+
+// projectMul places the product qᵀ·a into dst. When a is a band matrix the
+// product is formed one row at a time as (aᵀ·q_j)ᵀ using banded matrix-vector
+// products.
+func projectMul(dst *Dense, q *Dense, a Matrix) {
+	b, t, ok := rawBandOf(a)
+	if !ok {
+		dst.Mul(q.T(), a)
+		return
+	}
+	_, c := a.Dims()
+	_, k := q.Dims()
+	dst.reuseAsNonZeroed(k, c)
+	if t == blas.NoTrans {
+		t = blas.Trans
+	} else {
+		t = blas.NoTrans
+	}
+	for j := 0; j < k; j++ {
+		x := blas64.Vector{N: q.mat.Rows, Inc: q.mat.Stride, Data: q.mat.Data[j:]}
+		y := blas64.Vector{N: c, Inc: 1, Data: dst.rawRowView(j)}
+		blas64.Gbmv(t, 1, b, x, 0, y)
+	}
+}
 
-	for i := range data {
-		data[i] = rand.Float64()
+// projectMulColumnBlocked places the product qᵀ·a into dst, computing it in
+// blocks of at most width columns of a. Unless a is a band matrix, a Dense or
+// a transposed Dense, each block of a is copied into an m×width workspace.
+func projectMulColumnBlocked(dst *Dense, q *Dense, a Matrix, width int) {
+	if _, _, ok := rawBandOf(a); ok {
+		projectMul(dst, q, a)
+		return
+	}
+	r, c := a.Dims()
+	_, k := q.Dims()
+	u, trans := untransposeExtract(a)
+	if d, ok := u.(*Dense); ok {
+		dst.reuseAsNonZeroed(k, c)
+		for j := 0; j < c; j += width {
+			l := min(j+width, c)
+			var blk Matrix
+			if trans {
+				blk = d.slice(j, l, 0, r).T()
+			} else {
+				blk = d.slice(0, r, j, l)
+			}
+			dst.slice(0, k, j, l).Mul(q.T(), blk)
+		}
+		return
+	}
+	dst.reuseAsNonZeroed(k, c)
+	w := getWorkspace(r, min(width, c), false)
+	defer putWorkspace(w)
+	for j := 0; j < c; j += width {
+		l := min(j+width, c)
+		blk := w.slice(0, r, 0, l-j)
+		for i := 0; i < r; i++ {
+			row := blk.rawRowView(i)
+			for jj := range row {
+				row[jj] = a.At(i, j+jj)
+			}
+		}
+		dst.slice(0, k, j, l).Mul(q.T(), blk)
 	}
+}
 
-	return NewDense(rows, columns, data)
+// rawBandOf returns the raw band representation of a and the transpose
+// operation that must be applied to it to obtain a. If a is not a band
+// matrix, rawBandOf returns false.
+func rawBandOf(a Matrix) (blas64.Band, blas.Transpose, bool) {
+	u, trans := untransposeExtract(a)
+	b, ok := u.(*BandDense)
+	if !ok {
+		return blas64.Band{}, blas.NoTrans, false
+	}
+	if trans {
+		return b.mat, blas.Trans, true
+	}
+	return b.mat, blas.NoTrans, true
 }
@@ -0,0 +1,2444 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"log"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func randBandDense(r, c, kl, ku int, rnd *rand.Rand) *BandDense {
+	b := NewBandDense(r, c, kl, ku, nil)
+	for i := 0; i < r; i++ {
+		for j := max(0, i-kl); j < min(c, i+ku+1); j++ {
+			b.SetBand(i, j, rnd.NormFloat64())
+		}
+	}
+	return b
+}
+
+// hasOrthonormalColumns returns whether the columns of a are orthonormal
+// to within tol.
+func hasOrthonormalColumns(a Matrix, tol float64) bool {
+	_, c := a.Dims()
+	var ata Dense
+	ata.Mul(a.T(), a)
+	return EqualApprox(&ata, eye(c), tol)
+}
+
+func TestRSVDZeroValue(t *testing.T) {
+	t.Parallel()
+	// A zero value RSVD must be usable without initialization,
+	// and the factors must have the requested rank.
+	const m, n, rank = 6, 4, 2
+	a := NewDense(m, n, []float64{
+		1, 2, 0, 1,
+		0, 1, 3, 1,
+		2, 0, 1, 0,
+		1, 1, 1, 1,
+		3, 0, 2, 1,
+		0, 2, 1, 3,
+	})
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var u Dense
+	rsvd.UTo(&u)
+	if r, c := u.Dims(); r != m || c != rank {
+		t.Errorf("unexpected dimensions of U: got:%d×%d want:%d×%d", r, c, m, rank)
+	}
+}
+
+func TestRSVDBandedMul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		r, c, kl, ku, k int
+	}{
+		{r: 10, c: 10, kl: 1, ku: 1, k: 3},
+		{r: 20, c: 7, kl: 3, ku: 2, k: 4},
+		{r: 7, c: 20, kl: 0, ku: 5, k: 2},
+		{r: 15, c: 15, kl: 6, ku: 0, k: 15},
+	} {
+		band := randBandDense(test.r, test.c, test.kl, test.ku, rnd)
+		for _, a := range []Matrix{band, band.T(), band.TBand()} {
+			ar, ac := a.Dims()
+			dense := DenseCopyOf(a)
+
+			p := NewDense(ac, test.k, nil)
+			for i := range p.mat.Data {
+				p.mat.Data[i] = rnd.NormFloat64()
+			}
+			var got, want Dense
+			sketchMul(&got, a, p)
+			want.Mul(dense, p)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("case %d: unexpected banded sketch product", cas)
+			}
+
+			q := NewDense(ar, test.k, nil)
+			for i := range q.mat.Data {
+				q.mat.Data[i] = rnd.NormFloat64()
+			}
+			got.Reset()
+			want.Reset()
+			projectMul(&got, q, a)
+			want.Mul(q.T(), dense)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("case %d: unexpected banded projection product", cas)
+			}
+		}
+	}
+}
+
+func TestRSVDBandedFactorize(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 12
+	a := randBandDense(n, n, 2, 3, rnd)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, n) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	s := NewDiagDense(n, rsvd.Values(nil))
+
+	var got Dense
+	got.Product(&u, s, v.T())
+	if !EqualApprox(&got, a, 1e-10) {
+		t.Errorf("unexpected reconstruction of full rank band matrix")
+	}
+}
+
+func TestRSVDQOrthonormalityError(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewDense(30, 20, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = rnd.NormFloat64()
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 5) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if e := rsvd.QOrthonormalityError(); e > 1e-12 {
+		t.Errorf("unexpected loss of orthogonality: %v", e)
+	}
+
+	for i := 0; i < 30; i++ {
+		rsvd.q.Set(i, 0, 2*rsvd.q.At(i, 0))
+	}
+	if e := rsvd.QOrthonormalityError(); math.Abs(e-3) > 1e-12 {
+		t.Errorf("loss of orthogonality not detected: %v", e)
+	}
+}
+
+func TestRSVDStandardize(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 20, 6
+	a := NewDense(m, n, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, math.Pow(10, float64(j))*rnd.NormFloat64())
+		}
+	}
+	orig := DenseCopyOf(a)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, n, RSVDStandardize()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if !Equal(a, orig) {
+		t.Error("input matrix modified")
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	s := NewDiagDense(n, rsvd.Values(nil))
+
+	var got Dense
+	got.Product(&u, s, v.T())
+	if !EqualApprox(&got, a, 1e-8) {
+		t.Errorf("unexpected reconstruction of standardized factorization")
+	}
+
+	// The singular values must be those of the
+	// column-standardized matrix.
+	sd := colStdDevs(a)
+	var scaled Dense
+	scaled.Apply(func(i, j int, v float64) float64 { return v / sd[j] }, a)
+	var svd SVD
+	svd.Factorize(&scaled, SVDNone)
+	want := svd.Values(nil)
+	gotValues := rsvd.Values(nil)
+	for i := range want {
+		if math.Abs(gotValues[i]-want[i]) > 1e-10*want[0] {
+			t.Errorf("unexpected singular value %d: got:%v want:%v", i, gotValues[i], want[i])
+		}
+	}
+}
+
+func TestRSVDFullRankFallback(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 10, n: 10, rank: 10},
+		{m: 10, n: 6, rank: 6},
+		{m: 6, n: 10, rank: 6},
+		{m: 6, n: 10, rank: 20},
+		{m: 12, n: 4, rank: 5},
+	} {
+		a := NewDense(test.m, test.n, nil)
+		for i := range a.mat.Data {
+			a.mat.Data[i] = rnd.NormFloat64()
+		}
+		var rsvd RSVD
+		if !rsvd.Factorize(a, test.rank) {
+			t.Fatalf("unexpected factorization failure for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+
+		var svd SVD
+		svd.Factorize(a, SVDThin)
+		var wantU, wantV, gotU, gotV Dense
+		svd.UTo(&wantU)
+		svd.VTo(&wantV)
+		rsvd.UTo(&gotU)
+		rsvd.VTo(&gotV)
+		if !Equal(&gotU, &wantU) || !Equal(&gotV, &wantV) {
+			t.Errorf("unexpected factors for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+		if !floats.Equal(rsvd.Values(nil), svd.Values(nil)) {
+			t.Errorf("unexpected singular values for %d×%d rank %d", test.m, test.n, test.rank)
+		}
+	}
+}
+
+func TestRSVDToSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 5
+	a := NewDense(m, n, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = rnd.NormFloat64()
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank) {
+		t.Fatal("unexpected factorization failure")
+	}
+	svd := rsvd.ToSVD()
+	if svd.Kind() != SVDThin {
+		t.Errorf("unexpected kind: got:%v want:%v", svd.Kind(), SVDThin)
+	}
+
+	var gotU, gotV, wantU, wantV Dense
+	svd.UTo(&gotU)
+	svd.VTo(&gotV)
+	rsvd.UTo(&wantU)
+	rsvd.VTo(&wantV)
+	if !Equal(&gotU, &wantU) {
+		t.Error("unexpected U")
+	}
+	if !Equal(&gotV, &wantV) {
+		t.Error("unexpected V")
+	}
+	if !floats.Equal(svd.Values(nil), rsvd.Values(nil)) {
+		t.Error("unexpected singular values")
+	}
+
+	// The returned SVD must not share storage with the receiver.
+	svd.u.Data[0] = math.NaN()
+	rsvd.UTo(&gotU)
+	if !Equal(&gotU, &wantU) {
+		t.Error("SVD shares storage with RSVD")
+	}
+}
+
+func TestRSVDZeroAndRankDeficient(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 12, 9, 4
+
+	low := NewDense(m, n, nil)
+	for k := 0; k < 2; k++ {
+		x := NewVecDense(m, nil)
+		y := NewVecDense(n, nil)
+		for i := 0; i < m; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		for j := 0; j < n; j++ {
+			y.SetVec(j, rnd.NormFloat64())
+		}
+		low.RankOne(low, 1, x, y)
+	}
+
+	for _, test := range []struct {
+		name string
+		a    *Dense
+	}{
+		{name: "zero", a: NewDense(m, n, nil)},
+		{name: "rank 2", a: low},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(test.a, rank) {
+			t.Fatalf("%s: unexpected factorization failure", test.name)
+		}
+		var u, v Dense
+		rsvd.UTo(&u)
+		rsvd.VTo(&v)
+		s := rsvd.Values(nil)
+		if floats.HasNaN(u.mat.Data) || floats.HasNaN(v.mat.Data) || floats.HasNaN(s) {
+			t.Errorf("%s: unexpected NaN in factors", test.name)
+		}
+		if !hasOrthonormalColumns(&u, 1e-12) {
+			t.Errorf("%s: U not orthonormal", test.name)
+		}
+		var got Dense
+		got.Product(&u, NewDiagDense(rank, s), v.T())
+		if !EqualApprox(&got, test.a, 1e-12) {
+			t.Errorf("%s: unexpected reconstruction", test.name)
+		}
+	}
+}
+
+func TestRSVDResidualTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 15, 10, 3
+	a := NewDense(m, n, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = rnd.NormFloat64()
+	}
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var u, v Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	var want Dense
+	want.Product(&u, NewDiagDense(rank, rsvd.Values(nil)), v.T())
+	want.Sub(a, &want)
+
+	var got Dense
+	rsvd.ResidualTo(&got, a)
+	if !EqualApprox(&got, &want, 1e-12) {
+		t.Error("unexpected residual")
+	}
+
+	// The residual must be orthogonal to the captured left
+	// singular subspace.
+	var utr Dense
+	utr.Mul(u.T(), &got)
+	if !EqualApprox(&utr, NewDense(rank, n, nil), 1e-12) {
+		t.Error("residual not orthogonal to U")
+	}
+
+	panicked, _ := panics(func() { rsvd.ResidualTo(&got, a.T()) })
+	if !panicked {
+		t.Error("expected panic for mismatched matrix shape")
+	}
+}
+
+func TestRSVDAccurateInner(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 40, 30, 4
+	a := randLowRank(m, n, rank, rnd)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank, RSVDAccurateInner()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var svd SVD
+	svd.Factorize(a, SVDNone)
+	want := svd.Values(nil)[:rank]
+	got := rsvd.Values(nil)
+	if !floats.EqualApprox(got, want, 1e-10) {
+		t.Errorf("unexpected singular values:\ngot: %v\nwant:%v", got, want)
+	}
+	var u, v, rec Dense
+	rsvd.UTo(&u)
+	rsvd.VTo(&v)
+	rec.Product(&u, NewDiagDense(rank, got), v.T())
+	if !EqualApprox(&rec, a, 1e-10) {
+		t.Error("unexpected reconstruction")
+	}
+}
+
+func TestLowRankApprox(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 4
+	a := randLowRank(m, n, rank, rnd)
+
+	var got Dense
+	err := LowRankApprox(&got, a, rank, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !EqualApprox(&got, a, 1e-10) {
+		t.Error("unexpected approximation of exactly low rank matrix")
+	}
+
+	// The approximation must be reproducible from the seed.
+	var first, second Dense
+	b := NewDense(m, n, nil)
+	for i := range b.mat.Data {
+		b.mat.Data[i] = rnd.NormFloat64()
+	}
+	err = LowRankApprox(&first, b, rank, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = LowRankApprox(&second, b, rank, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !Equal(&first, &second) {
+		t.Error("approximation not reproducible with equal seeds")
+	}
+}
+
+func TestRSVDSymmetric(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 30
+	lambda := []float64{5, -4, 3, -2}
+	rank := len(lambda)
+
+	var g Dense
+	g.ReuseAs(n, n)
+	for i := range g.mat.Data {
+		g.mat.Data[i] = rnd.NormFloat64()
+	}
+	var qr QR
+	qr.Factorize(&g)
+	var w Dense
+	qr.QTo(&w)
+	a := NewSymDense(n, nil)
+	for k, l := range lambda {
+		x := NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			x.SetVec(i, w.At(i, k))
+		}
+		a.SymRankOne(a, l, x)
+	}
+
+	for _, r := range []int{rank, rank + 3, n} {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, r) {
+			t.Fatalf("unexpected factorization failure for rank %d", r)
+		}
+		eig := rsvd.Eigenvalues(nil)
+		if !floats.EqualApprox(eig[:rank], lambda, 1e-10) {
+			t.Errorf("unexpected eigenvalues for rank %d: got:%v want:%v", r, eig[:rank], lambda)
+		}
+		s := rsvd.Values(nil)
+		for i, v := range s {
+			if v != math.Abs(eig[i]) {
+				t.Errorf("singular value %d does not match eigenvalue for rank %d", i, r)
+			}
+		}
+
+		var u, v Dense
+		rsvd.UTo(&u)
+		rsvd.VTo(&v)
+		for j, l := range eig {
+			sign := 1.0
+			if l < 0 {
+				sign = -1
+			}
+			for i := 0; i < n; i++ {
+				if math.Abs(v.At(i, j)-sign*u.At(i, j)) > 1e-12 {
+					t.Fatalf("V inconsistent with U in column %d for rank %d", j, r)
+				}
+			}
+		}
+		var got Dense
+		got.Product(&u, NewDiagDense(len(s), s), v.T())
+		if !EqualApprox(&got, a, 1e-10) {
+			t.Errorf("unexpected reconstruction for rank %d", r)
+		}
+	}
+
+	var rsvd RSVD
+	rsvd.Factorize(DenseCopyOf(a), rank)
+	panicked, _ := panics(func() { rsvd.Eigenvalues(nil) })
+	if !panicked {
+		t.Error("expected panic for eigenvalues of general factorization")
+	}
+}
+
+func TestRSVDPivotedSketch(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, trueRank, rank = 40, 30, 3, 8
+	a := randLowRank(m, n, trueRank, rnd)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank, RSVDPivotedSketch(1e-10)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := rsvd.Rank(); got != trueRank {
+		t.Errorf("unexpected rank: got:%d want:%d", got, trueRank)
+	}
+	if e := rsvd.QOrthonormalityError(); e > 1e-12 {
+		t.Errorf("unexpected loss of orthogonality: %v", e)
+	}
+	var rec Dense
+	rsvd.ResidualTo(&rec, a)
+	if norm := Norm(&rec, 2); norm > 1e-10*Norm(a, 2) {
+		t.Errorf("unexpected residual norm: %v", norm)
+	}
+
+	// Without pivoting the requested rank is kept.
+	rsvd.Factorize(a, rank)
+	if got := rsvd.Rank(); got != rank {
+		t.Errorf("unexpected rank without pivoting: got:%d want:%d", got, rank)
+	}
+}
+
+func TestBestRankKError(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 12, 5
+	sigma := []float64{5, 4, 3, 2, 1}
+
+	// A = Q·diag(σ) has singular values σ for orthonormal Q.
+	var qr QR
+	qr.Factorize(NewRandomNormalDense(m, n, rnd))
+	var q Dense
+	qr.QTo(&q)
+	a := NewDense(m, n, nil)
+	for i := 0; i < m; i++ {
+		for j, s := range sigma {
+			a.set(i, j, q.at(i, j)*s)
+		}
+	}
+
+	for k := 0; k <= n+1; k++ {
+		var want float64
+		for i := k; i < len(sigma); i++ {
+			want += sigma[i] * sigma[i]
+		}
+		want = math.Sqrt(want)
+		got := BestRankKError(a, k)
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected error for k=%d: got:%v want:%v", k, got, want)
+		}
+		if k < 1 || k >= n {
+			continue
+		}
+
+		// No randomized approximation may beat the optimum.
+		var rsvd RSVD
+		if !rsvd.Factorize(a, k, withRand(rnd)) {
+			t.Fatalf("unexpected factorization failure for k=%d", k)
+		}
+		var res Dense
+		rsvd.ResidualTo(&res, a)
+		if r := Norm(&res, 2); r < got-1e-12 {
+			t.Errorf("randomized error below optimum for k=%d: got:%v optimum:%v", k, r, got)
+		}
+	}
+}
+
+func TestRSVDOrthoProjection(t *testing.T) {
+	t.Parallel()
+	const m, n, rank, trials = 40, 30, 5, 100
+
+	// A matrix with a slowly decaying spectrum makes the
+	// randomized singular values sensitive to the projection.
+	rnd := rand.New(rand.NewSource(1))
+	var qu, qv QR
+	qu.Factorize(NewRandomNormalDense(m, n, rnd))
+	qv.Factorize(NewRandomNormalDense(n, n, rnd))
+	var u, v Dense
+	qu.QTo(&u)
+	qv.QTo(&v)
+	sigma := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		sigma.SetDiag(i, 1/float64(i+1))
+	}
+	var a Dense
+	a.Product(u.Slice(0, m, 0, n), sigma, v.T())
+
+	var rsvd RSVD
+	if !rsvd.Factorize(&a, rank, withRand(rnd), RSVDOrthoProjection()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var p Dense
+	rsvd.VTo(&p)
+	if !hasOrthonormalColumns(&p, 1e-12) {
+		t.Error("right singular vectors not orthonormal")
+	}
+
+	variance := func(opts ...RSVDOption) float64 {
+		rnd := rand.New(rand.NewSource(2))
+		var sum, sumSq float64
+		for i := 0; i < trials; i++ {
+			var rsvd RSVD
+			if !rsvd.Factorize(&a, rank, append(opts, withRand(rnd))...) {
+				t.Fatal("unexpected factorization failure")
+			}
+			var d float64
+			for j, s := range rsvd.Values(nil) {
+				e := s - sigma.At(j, j)
+				d += e * e
+			}
+			sum += d
+			sumSq += d * d
+		}
+		mean := sum / trials
+		return sumSq/trials - mean*mean
+	}
+	plain := variance()
+	ortho := variance(RSVDOrthoProjection())
+	if ortho >= plain {
+		t.Errorf("orthonormal projection did not reduce variance: got:%v plain:%v", ortho, plain)
+	}
+}
+
+func TestRSVDProjector(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 4
+	a := randLowRank(m, n, rank, rnd)
+	for j := 0; j < n; j++ {
+		for i := 0; i < m; i++ {
+			a.set(i, j, a.at(i, j)*float64(j+1))
+		}
+	}
+
+	for _, opts := range [][]RSVDOption{
+		nil,
+		{RSVDStandardize()},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, rank, append(opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		p := rsvd.Projector()
+		if r, c := p.Dims(); r != n || c != rank {
+			t.Errorf("unexpected projector dimensions: got:%d×%d want:%d×%d", r, c, n, rank)
+		}
+
+		// Projecting A must give its coordinates U Σ.
+		var got, want, u Dense
+		got.Mul(a, p)
+		rsvd.UTo(&u)
+		want.Mul(&u, NewDiagDense(rank, rsvd.Values(nil)))
+		if !EqualApprox(&got, &want, 1e-10) {
+			t.Errorf("unexpected projection of A with %d options", len(opts))
+		}
+	}
+}
+
+func TestRSVDMinRank(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(10, 6, rnd)
+
+	for _, test := range []struct {
+		rank int
+		opts []RSVDOption
+		want string
+	}{
+		{rank: 0, want: "mat: rank 0 for 10×6 matrix is less than the minimum rank 1"},
+		{rank: 2, opts: []RSVDOption{RSVDMinRank(3)}, want: "mat: rank 2 for 10×6 matrix is less than the minimum rank 3"},
+		{rank: 8, opts: []RSVDOption{RSVDMinRank(7)}, want: "mat: minimum rank 7 for 10×6 matrix is greater than min(m,n) = 6"},
+		{rank: 3, opts: []RSVDOption{RSVDMinRank(3)}},
+		{rank: 8, opts: []RSVDOption{RSVDMinRank(6)}},
+	} {
+		var rsvd RSVD
+		panicked, msg := panics(func() { rsvd.Factorize(a, test.rank, append(test.opts, withRand(rnd))...) })
+		if panicked != (test.want != "") {
+			t.Errorf("unexpected panic status for rank %d: got:%t want:%t", test.rank, panicked, !panicked)
+			continue
+		}
+		if panicked && msg != test.want {
+			t.Errorf("unexpected panic message for rank %d: got:%q want:%q", test.rank, msg, test.want)
+		}
+	}
+
+	if p, _ := panics(func() { RSVDMinRank(0) }); !p {
+		t.Error("expected panic for non-positive minimum rank")
+	}
+}
+
+func TestRSVDFactors(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 25, 15, 3
+	a := randLowRank(m, n, rank, rnd)
+
+	var rsvd RSVD
+	if p, _ := panics(func() { rsvd.Factors() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+	if !rsvd.Factorize(a, rank, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	u, sigma, v := rsvd.Factors()
+
+	var wantU, wantV Dense
+	rsvd.UTo(&wantU)
+	rsvd.VTo(&wantV)
+	if !Equal(u, &wantU) {
+		t.Error("unexpected U factor")
+	}
+	if !Equal(v, &wantV) {
+		t.Error("unexpected V factor")
+	}
+	if !Equal(sigma, NewDiagDense(rank, rsvd.Values(nil))) {
+		t.Error("unexpected Σ factor")
+	}
+
+	var got Dense
+	got.Product(u, sigma, v.T())
+	if !EqualApprox(&got, a, 1e-10) {
+		t.Error("unexpected reconstruction from factors")
+	}
+
+	var diag DiagDense
+	rsvd.SigmaTo(&diag)
+	if !Equal(&diag, sigma) {
+		t.Error("unexpected Σ from SigmaTo")
+	}
+	view := NewDense(rank, rank, nil).DiagView().(*DiagDense)
+	rsvd.SigmaTo(view)
+	if !Equal(view, sigma) {
+		t.Error("unexpected Σ from SigmaTo into strided view")
+	}
+	var approx Dense
+	rsvd.ApproxTo(&approx)
+	if !EqualApprox(&approx, &got, 1e-12) {
+		t.Error("unexpected approximation from ApproxTo")
+	}
+	if p, _ := panics(func() { rsvd.SigmaTo(NewDiagDense(rank+1, nil)) }); !p {
+		t.Error("expected panic for wrong sized Σ destination")
+	}
+	if p, _ := panics(func() { rsvd.ApproxTo(NewDense(m, n+1, nil)) }); !p {
+		t.Error("expected panic for wrong sized approximation destination")
+	}
+}
+
+func TestRSVDColumnBlock(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, k = 15, 23, 4
+	d := NewRandomNormalDense(m, n, rnd)
+	tri := NewTriDense(n, Upper, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			tri.SetTri(i, j, rnd.NormFloat64())
+		}
+	}
+	for _, a := range []Matrix{d, d.T(), tri, tri.T(), randBandDense(m, n, 2, 3, rnd)} {
+		r, _ := a.Dims()
+		q := NewRandomNormalDense(r, k, rnd)
+		var want Dense
+		want.Mul(q.T(), a)
+		for _, width := range []int{1, 5, n, 2 * n} {
+			var got Dense
+			projectMulColumnBlocked(&got, q, a, width)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("unexpected column blocked product for %T width=%d", a, width)
+			}
+		}
+	}
+
+	a := randLowRank(40, 30, 5, rnd)
+	var plain, blocked RSVD
+	if !plain.Factorize(a, 5, withRand(rand.New(rand.NewSource(2)))) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if !blocked.Factorize(a, 5, withRand(rand.New(rand.NewSource(2))), RSVDColumnBlock(7)) {
+		t.Fatal("unexpected factorization failure with column blocks")
+	}
+	if !floats.EqualApprox(plain.Values(nil), blocked.Values(nil), 1e-12) {
+		t.Error("column blocked singular values differ from unblocked")
+	}
+
+	if p, _ := panics(func() { RSVDColumnBlock(0) }); !p {
+		t.Error("expected panic for non-positive width")
+	}
+}
+
+func TestCompareSpectra(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 4
+
+	// Exactly low rank matrices are recovered to working precision.
+	a := randLowRank(m, n, rank, rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := CompareSpectra(&rsvd, a); got > 1e-10 {
+		t.Errorf("unexpected spectral error for low rank matrix: %v", got)
+	}
+
+	// Perturbing the randomized values must be reported.
+	rsvd.svd.s[1] *= 1.5
+	if got := CompareSpectra(&rsvd, a); math.Abs(got-0.5) > 1e-10 {
+		t.Errorf("unexpected spectral error for perturbed values: got:%v want:0.5", got)
+	}
+
+	// Randomized singular values never exceed the true values.
+	b := NewRandomNormalDense(m, n, rnd)
+	if !rsvd.Factorize(b, rank, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := CompareSpectra(&rsvd, b); got <= 0 || got >= 1 {
+		t.Errorf("unexpected spectral error for full rank matrix: %v", got)
+	}
+
+	if p, _ := panics(func() { CompareSpectra(&rsvd, a.T()) }); !p {
+		t.Error("expected panic for mismatched matrix")
+	}
+}
+
+func TestRSVDCoherence(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 16, 10
+
+	// A matrix whose range contains standard basis vectors
+	// is maximally coherent.
+	a := NewDense(m, n, nil)
+	a.set(0, 0, 3)
+	a.set(1, 1, 2)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 2, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got, want := rsvd.Coherence(), float64(m)/2; math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected coherence of coordinate subspace: got:%v want:%v", got, want)
+	}
+
+	// The constant vector spans a subspace of coherence one.
+	b := NewDense(m, n, nil)
+	for i := range b.mat.Data {
+		b.mat.Data[i] = 1
+	}
+	if !rsvd.Factorize(b, 1, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := rsvd.Coherence(); math.Abs(got-1) > 1e-12 {
+		t.Errorf("unexpected coherence of constant subspace: got:%v want:1", got)
+	}
+
+	c := NewRandomNormalDense(m, n, rnd)
+	if !rsvd.Factorize(c, 3, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := rsvd.Coherence(); got < 1-1e-12 || float64(m)/3+1e-12 < got {
+		t.Errorf("coherence out of range: %v", got)
+	}
+}
+
+func TestRSVDInnerSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 4
+	a := randLowRank(m, n, rank, rnd)
+	sym := NewSymDense(m, nil)
+	for i := 0; i < m; i++ {
+		for j := i; j < m; j++ {
+			sym.SetSym(i, j, rnd.NormFloat64())
+		}
+	}
+
+	for _, test := range []struct {
+		a    Matrix
+		rank int
+	}{
+		{a: a, rank: rank},
+		{a: sym, rank: rank},
+		{a: a, rank: n},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(test.a, test.rank, withRand(rnd)) {
+			t.Fatal("unexpected factorization failure")
+		}
+		inner := rsvd.InnerSVD()
+		if !floats.Equal(inner.Values(nil), rsvd.Values(nil)) {
+			t.Errorf("inner singular values differ for %T rank %d", test.a, test.rank)
+		}
+
+		// Lifting the inner left singular vectors must give U.
+		var uy, u, want Dense
+		inner.UTo(&uy)
+		rsvd.UTo(&want)
+		if rsvd.q == nil {
+			u.CloneFrom(&uy)
+		} else {
+			u.Mul(rsvd.q, &uy)
+		}
+		if !EqualApprox(&u, &want, 1e-12) {
+			t.Errorf("unexpected lift of inner U for %T rank %d", test.a, test.rank)
+		}
+		var v, wantV Dense
+		inner.VTo(&v)
+		rsvd.VTo(&wantV)
+		if !Equal(&v, &wantV) {
+			t.Errorf("unexpected inner V for %T rank %d", test.a, test.rank)
+		}
+	}
+}
+
+func TestRSVDPowerIter(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 60, 40, 5
+
+	// A slowly decaying spectrum is only captured
+	// accurately by a sketch with power iterations.
+	var qu, qv QR
+	qu.Factorize(NewRandomNormalDense(m, n, rnd))
+	qv.Factorize(NewRandomNormalDense(n, n, rnd))
+	var u, v Dense
+	qu.QTo(&u)
+	qv.QTo(&v)
+	sigma := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		sigma.SetDiag(i, 1/math.Sqrt(float64(i+1)))
+	}
+	var a Dense
+	a.Product(u.Slice(0, m, 0, n), sigma, v.T())
+
+	var errs []float64
+	for _, q := range []int{0, 1, 3} {
+		var rsvd RSVD
+		if !rsvd.Factorize(&a, rank, withRand(rand.New(rand.NewSource(2))), RSVDPowerIter(q)) {
+			t.Fatalf("unexpected factorization failure for q=%d", q)
+		}
+		stats := rsvd.Stats()
+		if stats.PowerIterations != q {
+			t.Errorf("unexpected number of power iterations: got:%d want:%d", stats.PowerIterations, q)
+		}
+		if stats.OrthoLoss > 1e-13 || stats.OrthoLossExceeded {
+			t.Errorf("unexpected loss of orthogonality for q=%d: %v", q, stats.OrthoLoss)
+		}
+		var res Dense
+		rsvd.ResidualTo(&res, &a)
+		errs = append(errs, Norm(&res, 2))
+	}
+	best := BestRankKError(&a, rank)
+	for i := 1; i < len(errs); i++ {
+		if errs[i] >= errs[i-1] {
+			t.Errorf("power iteration did not improve accuracy: %v", errs)
+		}
+	}
+	if errs[len(errs)-1] > 1.05*best {
+		t.Errorf("unexpected error after power iterations: got:%v best:%v", errs[len(errs)-1], best)
+	}
+
+	// Standardized and power iterated decompositions must agree with
+	// decompositions of the explicitly standardized matrix.
+	sd := colStdDevs(&a)
+	var scaled Dense
+	scaled.Apply(func(i, j int, v float64) float64 { return v / sd[j] }, &a)
+	var std, want RSVD
+	std.Factorize(&a, rank, withRand(rand.New(rand.NewSource(3))), RSVDStandardize(), RSVDPowerIter(2))
+	want.Factorize(&scaled, rank, withRand(rand.New(rand.NewSource(3))), RSVDPowerIter(2))
+	if !floats.EqualApprox(std.Values(nil), want.Values(nil), 1e-10) {
+		t.Error("unexpected singular values of standardized power iteration")
+	}
+
+	var rsvd RSVD
+	if p, _ := panics(func() { rsvd.Stats() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+	if p, _ := panics(func() { RSVDPowerIter(-1) }); !p {
+		t.Error("expected panic for negative power iterations")
+	}
+}
+
+func TestRSVDSeedFromInput(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(30, 20, rnd)
+
+	factorize := func(a Matrix, opts ...RSVDOption) []float64 {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, 4, append(opts, RSVDSeedFromInput())...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		return rsvd.Values(nil)
+	}
+	first := factorize(a)
+	if !floats.Equal(first, factorize(DenseCopyOf(a))) {
+		t.Error("factorization of equal matrices not reproducible")
+	}
+	// The derived seed overrides other sources.
+	if !floats.Equal(first, factorize(a, withRand(rnd))) {
+		t.Error("derived seed overridden by source option")
+	}
+
+	b := DenseCopyOf(a)
+	b.Set(0, 0, math.Nextafter(b.At(0, 0), math.Inf(1)))
+	if inputSeed(a) == inputSeed(b) {
+		t.Error("equal seeds for matrices differing in a sampled element")
+	}
+	if inputSeed(a) == inputSeed(a.T()) {
+		t.Error("equal seeds for matrices with different dimensions")
+	}
+}
+
+func TestSubspaceAngle(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 20, 10
+
+	// Two rank two matrices whose ranges share e₀ and whose second
+	// directions are e₁ and cos(θ) e₁ + sin(θ) e₂.
+	const theta = 0.3
+	a := NewDense(m, n, nil)
+	a.set(0, 0, 2)
+	a.set(1, 1, 1)
+	b := NewDense(m, n, nil)
+	b.set(0, 3, 5)
+	b.set(1, 4, 3*math.Cos(theta))
+	b.set(2, 4, 3*math.Sin(theta))
+
+	var ra, rb RSVD
+	if !ra.Factorize(a, 2, withRand(rnd)) || !rb.Factorize(b, 2, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	got := SubspaceAngle(&ra, &rb)
+	want := []float64{0, theta}
+	if !floats.EqualApprox(got, want, 1e-7) {
+		t.Errorf("unexpected principal angles: got:%v want:%v", got, want)
+	}
+	if got := SubspaceAngle(&ra, &ra); !floats.EqualApprox(got, []float64{0, 0}, 1e-7) {
+		t.Errorf("unexpected principal angles of a subspace with itself: %v", got)
+	}
+
+	var rc RSVD
+	if !rc.Factorize(NewRandomNormalDense(m+1, n, rnd), 2, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if p, _ := panics(func() { SubspaceAngle(&ra, &rc) }); !p {
+		t.Error("expected panic for mismatched row dimensions")
+	}
+}
+
+func TestRSVDAuto(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+		wantPower  int
+	}{
+		{m: 80, n: 30, rank: 4, wantPower: 1},
+		{m: 30, n: 80, rank: 4, wantPower: 1},
+		{m: 200, n: 120, rank: 5, wantPower: 2},
+		{m: 15, n: 12, rank: 3, wantPower: 0},
+	} {
+		// A matrix with a slowly decaying spectrum.
+		sv := make([]float64, min(test.m, test.n))
+		for i := range sv {
+			sv[i] = 1 / float64(i+1)
+		}
+		var qu, qv QR
+		qu.Factorize(NewRandomNormalDense(test.m, len(sv), rnd))
+		qv.Factorize(NewRandomNormalDense(test.n, len(sv), rnd))
+		var u, v Dense
+		qu.QTo(&u)
+		qv.QTo(&v)
+		var a Dense
+		a.Product(u.Slice(0, test.m, 0, len(sv)), NewDiagDense(len(sv), sv), v.Slice(0, test.n, 0, len(sv)).T())
+
+		var rsvd RSVD
+		if !rsvd.Factorize(&a, test.rank, withRand(rnd), RSVDAuto()) {
+			t.Fatalf("%d×%d: unexpected factorization failure", test.m, test.n)
+		}
+		if got := rsvd.Stats().PowerIterations; got != test.wantPower {
+			t.Errorf("%d×%d: unexpected number of power iterations: got:%d want:%d", test.m, test.n, got, test.wantPower)
+		}
+		if got := rsvd.Rank(); got != test.rank {
+			t.Errorf("%d×%d: unexpected rank: got:%d want:%d", test.m, test.n, got, test.rank)
+		}
+		var gotU, gotV Dense
+		rsvd.UTo(&gotU)
+		rsvd.VTo(&gotV)
+		if r, c := gotU.Dims(); r != test.m || c != test.rank {
+			t.Errorf("%d×%d: unexpected dimensions of U: %d×%d", test.m, test.n, r, c)
+		}
+		if r, c := gotV.Dims(); r != test.n || c != test.rank {
+			t.Errorf("%d×%d: unexpected dimensions of V: %d×%d", test.m, test.n, r, c)
+		}
+		if !hasOrthonormalColumns(&gotU, 1e-12) || !hasOrthonormalColumns(&gotV, 1e-12) {
+			t.Errorf("%d×%d: singular vectors not orthonormal", test.m, test.n)
+		}
+		var res Dense
+		rsvd.ResidualTo(&res, &a)
+		if got, best := Norm(&res, 2), BestRankKError(&a, test.rank); got > 1.1*best {
+			t.Errorf("%d×%d: unexpected approximation error: got:%v best:%v", test.m, test.n, got, best)
+		}
+
+		// Explicit power iterations override the automatic choice.
+		for _, opts := range [][]RSVDOption{
+			{RSVDAuto(), RSVDPowerIter(3)},
+			{RSVDPowerIter(3), RSVDAuto()},
+		} {
+			if !rsvd.Factorize(&a, test.rank, append(opts, withRand(rnd))...) {
+				t.Fatalf("%d×%d: unexpected factorization failure", test.m, test.n)
+			}
+			if got := rsvd.Stats().PowerIterations; got != 3 {
+				t.Errorf("%d×%d: explicit power iterations not used: got:%d want:3", test.m, test.n, got)
+			}
+		}
+	}
+}
+
+func TestRSVDRowColView(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		m, n, rank int
+		opts       []RSVDOption
+	}{
+		{m: 20, n: 12, rank: 4},
+		{m: 12, n: 20, rank: 4},
+		{m: 20, n: 12, rank: 4, opts: []RSVDOption{RSVDStandardize()}},
+		{m: 30, n: 12, rank: 3, opts: []RSVDOption{RSVDAuto()}},
+		{m: 8, n: 6, rank: 6},
+	} {
+		a := NewRandomNormalDense(test.m, test.n, rnd)
+		var rsvd RSVD
+		if !rsvd.Factorize(a, test.rank, append(test.opts, withRand(rnd))...) {
+			t.Fatalf("case %d: unexpected factorization failure", cas)
+		}
+		u, sigma, v := rsvd.Factors()
+		var want Dense
+		want.Product(u, sigma, v.T())
+
+		for i := 0; i < test.m; i++ {
+			got := rsvd.RowView(i)
+			if !floats.EqualApprox(got, Row(nil, i, &want), 1e-12) {
+				t.Errorf("case %d: unexpected row %d", cas, i)
+			}
+		}
+		for j := 0; j < test.n; j++ {
+			got := rsvd.ColView(j)
+			if !floats.EqualApprox(got, Col(nil, j, &want), 1e-12) {
+				t.Errorf("case %d: unexpected column %d", cas, j)
+			}
+		}
+
+		for _, i := range []int{-1, test.m} {
+			if p, _ := panics(func() { rsvd.RowView(i) }); !p {
+				t.Errorf("case %d: expected panic for row %d", cas, i)
+			}
+		}
+		for _, j := range []int{-1, test.n} {
+			if p, _ := panics(func() { rsvd.ColView(j) }); !p {
+				t.Errorf("case %d: expected panic for column %d", cas, j)
+			}
+		}
+	}
+
+	var rsvd RSVD
+	if p, _ := panics(func() { rsvd.RowView(0) }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+}
+
+func TestRankSweep(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 40, 30
+	a := randLowRank(m, n, 6, rnd)
+
+	errs := RankSweep(a, 8, 2, rnd)
+	if len(errs) != 4 {
+		t.Fatalf("unexpected number of errors: got %d, want 4", len(errs))
+	}
+	norm := Norm(a, 2)
+	for i, got := range errs {
+		k := 2 * (i + 1)
+		want := BestRankKError(a, k) / norm
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("unexpected error at rank %d: got %v, want %v", k, got, want)
+		}
+		if i > 0 && got > errs[i-1] {
+			t.Errorf("error increased at rank %d", k)
+		}
+	}
+
+	// The oversampled sketch keeps the estimates near the best
+	// errors up to maxRank for a matrix with a decaying spectrum.
+	sv := make([]float64, n)
+	for i := range sv {
+		sv[i] = math.Pow(0.7, float64(i))
+	}
+	dec := NewTestMatrix(m, n, sv, rnd)
+	decNorm := Norm(dec, 2)
+	for i, got := range RankSweep(dec, 10, 5, rnd) {
+		k := 5 * (i + 1)
+		want := BestRankKError(dec, k) / decNorm
+		if math.Abs(got-want) > 0.05*want {
+			t.Errorf("unexpected error at rank %d for decaying spectrum: got %v, want %v", k, got, want)
+		}
+	}
+
+	if got := RankSweep(a, 5, 2, rnd); len(got) != 2 {
+		t.Errorf("unexpected number of errors for partial step: got %d, want 2", len(got))
+	}
+	for _, v := range RankSweep(NewDense(m, n, nil), 4, 1, rnd) {
+		if v != 0 {
+			t.Errorf("unexpected error for zero matrix: %v", v)
+		}
+	}
+	for _, test := range []struct{ maxRank, step int }{
+		{maxRank: 4, step: 0},
+		{maxRank: 1, step: 2},
+		{maxRank: n + 1, step: 1},
+	} {
+		if p, _ := panics(func() { RankSweep(a, test.maxRank, test.step, rnd) }); !p {
+			t.Errorf("expected panic for maxRank=%d step=%d", test.maxRank, test.step)
+		}
+	}
+}
+
+func TestRSVDSVTol(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 30, 20, 3
+	a := randLowRank(m, n, rank, rnd)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank+2, withRand(rnd), RSVDSVTol(1e-10)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	s := rsvd.Values(nil)
+	for i := rank; i < len(s); i++ {
+		if s[i] != 0 {
+			t.Errorf("singular value %d not zeroed: %v", i, s[i])
+		}
+	}
+
+	// Check the Moore–Penrose conditions A X A = A and X A X = X.
+	var pinv, axa, xax, tmp Dense
+	rsvd.PInvTo(&pinv)
+	if r, c := pinv.Dims(); r != n || c != m {
+		t.Fatalf("unexpected pseudoinverse size: got %d×%d, want %d×%d", r, c, n, m)
+	}
+	tmp.Mul(a, &pinv)
+	axa.Mul(&tmp, a)
+	if !EqualApprox(&axa, a, 1e-10) {
+		t.Error("pseudoinverse does not satisfy A X A = A")
+	}
+	tmp.Reset()
+	tmp.Mul(&pinv, a)
+	xax.Mul(&tmp, &pinv)
+	if !EqualApprox(&xax, &pinv, 1e-10) {
+		t.Error("pseudoinverse does not satisfy X A X = X")
+	}
+
+	b := NewRandomNormalDense(m, 2, rnd)
+	var got, want Dense
+	rsvd.SolveTo(&got, b)
+	want.Mul(&pinv, b)
+	if !EqualApprox(&got, &want, 1e-10) {
+		t.Error("unexpected least squares solution")
+	}
+	if p, _ := panics(func() { rsvd.SolveTo(&got, NewDense(m+1, 2, nil)) }); !p {
+		t.Error("expected panic for mismatched right-hand side")
+	}
+
+	// Without a tolerance the tiny singular values are inverted.
+	var plain RSVD
+	plain.Factorize(a, rank+2, withRand(rand.New(rand.NewSource(1))))
+	var big Dense
+	plain.PInvTo(&big)
+	if Norm(&big, 2) < 1e6*Norm(&pinv, 2) {
+		t.Error("expected pseudoinverse without tolerance to blow up")
+	}
+
+	if p, _ := panics(func() { RSVDSVTol(-1) }); !p {
+		t.Error("expected panic for negative tolerance")
+	}
+}
+
+func TestRSVDWasRandomized(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(20, 12, rnd)
+	sym := NewSymDense(12, nil)
+	sym.SymOuterK(1, NewRandomNormalDense(12, 4, rnd))
+	for _, test := range []struct {
+		a    Matrix
+		rank int
+		opts []RSVDOption
+		want bool
+	}{
+		{a: a, rank: 4, want: true},
+		{a: a, rank: 4, opts: []RSVDOption{RSVDAuto()}, want: true},
+		{a: a, rank: 12, want: false},
+		{a: sym, rank: 3, want: true},
+		{a: sym, rank: 12, want: false},
+		{a: NewDense(20, 12, nil), rank: 4, want: false},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(test.a, test.rank, append(test.opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if got := rsvd.WasRandomized(); got != test.want {
+			t.Errorf("unexpected result for %T rank %d: got:%t want:%t", test.a, test.rank, got, test.want)
+		}
+	}
+
+	var rsvd RSVD
+	if p, _ := panics(func() { rsvd.WasRandomized() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+}
+
+func TestLowRankFactorization(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 20, 12, 3
+	a := NewTestMatrix(m, n, []float64{4, 2, 1}, rnd)
+
+	// reconstruct forms U Σ Vᵀ using only the interface.
+	reconstruct := func(f LowRankFactorization) *Dense {
+		var u, v Dense
+		f.UTo(&u)
+		f.VTo(&v)
+		u.ScaleCols(f.Values(nil), &u)
+		r, c := f.Dims()
+		dst := NewDense(r, c, nil)
+		dst.Mul(&u, v.T())
+		return dst
+	}
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, rank, withRand(rnd)) {
+		t.Fatal("unexpected RSVD failure")
+	}
+	var tsvd TruncatedSVD
+	if !tsvd.Factorize(a, rank) {
+		t.Fatal("unexpected TruncatedSVD failure")
+	}
+	for _, f := range []LowRankFactorization{&rsvd, &tsvd} {
+		if r, c := f.Dims(); r != m || c != n {
+			t.Errorf("unexpected dimensions for %T: got %d×%d, want %d×%d", f, r, c, m, n)
+		}
+		if f.Rank() != rank {
+			t.Errorf("unexpected rank for %T: got %d, want %d", f, f.Rank(), rank)
+		}
+		if !EqualApprox(reconstruct(f), a, 1e-10) {
+			t.Errorf("unexpected reconstruction from %T", f)
+		}
+	}
+}
+
+func TestRSVDRangeError(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 30, 20
+	s := []float64{10, 8, 5, 3, 1, 0.5, 0.25, 0.1}
+	a := NewTestMatrix(m, n, s, rnd)
+
+	for _, test := range []struct {
+		rank int
+		opts []RSVDOption
+	}{
+		{rank: 3},
+		{rank: 3, opts: []RSVDOption{RSVDPowerIter(2)}},
+		{rank: 5, opts: []RSVDOption{RSVDAuto()}},
+		{rank: n},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, test.rank, append(test.opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		got := rsvd.RangeError(a)
+
+		// The range error never exceeds the error of the truncated factors,
+		// and cannot improve on the best approximation of the basis rank.
+		var res Dense
+		rsvd.ResidualTo(&res, a)
+		if resid := Norm(&res, 2); got > resid+1e-12 {
+			t.Errorf("rank %d: range error %v exceeds residual %v", test.rank, got, resid)
+		}
+		if rsvd.q != nil {
+			_, l := rsvd.q.Dims()
+			if best := BestRankKError(a, l); got < best-1e-12 {
+				t.Errorf("rank %d: range error %v below best error %v", test.rank, got, best)
+			}
+		}
+		if test.rank == n && got > 1e-12 {
+			t.Errorf("unexpected range error for deterministic factorization: %v", got)
+		}
+	}
+
+	var rsvd RSVD
+	rsvd.Factorize(a, 3, withRand(rnd))
+	if p, _ := panics(func() { rsvd.RangeError(NewDense(m, n+1, nil)) }); !p {
+		t.Error("expected panic for mismatched matrix")
+	}
+}
+
+func TestRSVDOrthonormalize(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(30, 18, rnd)
+	b := NewRandomNormalDense(18, 4, rnd)
+	sym := NewSymDense(18, nil)
+	sym.SymOuterK(1, b)
+	ev := NewVecDense(18, nil)
+	for i := 0; i < 18; i++ {
+		ev.SetVec(i, 2*rnd.NormFloat64())
+	}
+	sym.SymRankOne(sym, -1, ev)
+
+	for _, test := range []struct {
+		a    Matrix
+		opts []RSVDOption
+	}{
+		{a: a},
+		{a: a, opts: []RSVDOption{RSVDStandardize()}},
+		{a: sym},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(test.a, 4, append(test.opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		u, sigma, v := rsvd.Factors()
+		var want Dense
+		want.Product(u, sigma, v.T())
+		values := rsvd.Values(nil)
+		var eigs []float64
+		if _, ok := test.a.(Symmetric); ok {
+			eigs = rsvd.Eigenvalues(nil)
+		}
+
+		rsvd.Orthonormalize()
+		u, sigma, v = rsvd.Factors()
+		if e := orthoError(u); e > 1e-14 {
+			t.Errorf("%T: U not orthonormal: %v", test.a, e)
+		}
+		if rsvd.scale == nil {
+			if e := orthoError(v); e > 1e-14 {
+				t.Errorf("%T: V not orthonormal: %v", test.a, e)
+			}
+		}
+		var got Dense
+		got.Product(u, sigma, v.T())
+		if !EqualApprox(&got, &want, 1e-12) {
+			t.Errorf("%T: approximation changed by orthonormalization", test.a)
+		}
+		if !floats.EqualApprox(rsvd.Values(nil), values, 1e-12) {
+			t.Errorf("%T: singular values changed by orthonormalization", test.a)
+		}
+		if eigs != nil && !floats.EqualApprox(rsvd.Eigenvalues(nil), eigs, 1e-12) {
+			t.Errorf("%T: eigenvalues changed by orthonormalization", test.a)
+		}
+	}
+}
+
+func TestRSVDSkipUV(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(25, 15, rnd)
+	sym := NewSymDense(15, nil)
+	sym.SymOuterK(1, NewRandomNormalDense(15, 5, rnd))
+	for _, test := range []struct {
+		a    Matrix
+		rank int
+		opts []RSVDOption
+	}{
+		{a: a, rank: 4},
+		{a: a, rank: 4, opts: []RSVDOption{RSVDAccurateInner()}},
+		{a: a, rank: 15},
+		{a: sym, rank: 3},
+	} {
+		var want RSVD
+		if !want.Factorize(test.a, test.rank, append(test.opts, withRand(rand.New(rand.NewSource(2))))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		var wantU, wantV Dense
+		want.UTo(&wantU)
+		want.VTo(&wantV)
+		values := want.Values(nil)
+
+		for _, skip := range []struct{ u, v bool }{{u: true}, {v: true}, {u: true, v: true}} {
+			opts := append(test.opts, withRand(rand.New(rand.NewSource(2))))
+			if skip.u {
+				opts = append(opts, RSVDSkipU())
+			}
+			if skip.v {
+				opts = append(opts, RSVDSkipV())
+			}
+			var rsvd RSVD
+			if !rsvd.Factorize(test.a, test.rank, opts...) {
+				t.Fatal("unexpected factorization failure")
+			}
+			if !floats.EqualApprox(rsvd.Values(nil), values, 1e-12) {
+				t.Errorf("%T rank %d skip %+v: unexpected singular values", test.a, test.rank, skip)
+			}
+			if r, c := rsvd.Dims(); r != want.m || c != want.n {
+				t.Errorf("%T rank %d skip %+v: unexpected dimensions %d×%d", test.a, test.rank, skip, r, c)
+			}
+
+			var u, v Dense
+			panicked, msg := panics(func() { rsvd.UTo(&u) })
+			if skip.u {
+				if !panicked || msg != noU {
+					t.Errorf("%T rank %d skip %+v: expected panic for skipped U, got %q", test.a, test.rank, skip, msg)
+				}
+			} else if panicked || !EqualApprox(&u, &wantU, 1e-12) {
+				t.Errorf("%T rank %d skip %+v: unexpected U", test.a, test.rank, skip)
+			}
+			panicked, msg = panics(func() { rsvd.VTo(&v) })
+			if skip.v {
+				if !panicked || msg != noV {
+					t.Errorf("%T rank %d skip %+v: expected panic for skipped V, got %q", test.a, test.rank, skip, msg)
+				}
+			} else if panicked || !EqualApprox(&v, &wantV, 1e-12) {
+				t.Errorf("%T rank %d skip %+v: unexpected V", test.a, test.rank, skip)
+			}
+		}
+	}
+}
+
+func TestAdaptiveRangeFinder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 80, 60
+	s := make([]float64, 40)
+	for i := range s {
+		s[i] = math.Pow(2, -float64(i)/2)
+	}
+	a := NewTestMatrix(m, n, s, rnd)
+	tol := 1e-3 * Norm(a, 2)
+
+	cols := make(map[int]int)
+	for _, block := range []int{1, 4, rsvdAdaptiveBlock} {
+		q, resid := adaptiveRangeFinder(a, tol, block, min(m, n), rnd)
+		_, k := q.Dims()
+		cols[block] = k
+		if k%block != 0 {
+			t.Errorf("block %d: basis with %d columns is not a whole number of blocks", block, k)
+		}
+		if e := orthoError(q); e > 1e-12 {
+			t.Errorf("block %d: basis not orthonormal: %v", block, e)
+		}
+		var qta, r Dense
+		qta.Mul(q.T(), a)
+		r.Mul(q, &qta)
+		r.Sub(a, &r)
+		got := Norm(&r, 2)
+		if got > tol {
+			t.Errorf("block %d: range error %v exceeds tolerance %v", block, got, tol)
+		}
+		if math.Abs(got-resid) > 1e-6*Norm(a, 2) {
+			t.Errorf("block %d: unexpected tracked error: got:%v want:%v", block, resid, got)
+		}
+	}
+	if cols[rsvdAdaptiveBlock] < cols[1] || cols[rsvdAdaptiveBlock] >= cols[1]+rsvdAdaptiveBlock {
+		t.Errorf("unexpected overshoot of blocked basis: %d columns vs %d", cols[rsvdAdaptiveBlock], cols[1])
+	}
+
+	if q, resid := adaptiveRangeFinder(NewDense(m, n, nil), tol, 4, min(m, n), rnd); q != nil || resid != 0 {
+		t.Error("unexpected basis for zero matrix")
+	}
+	if p, _ := panics(func() { RSVDAdaptiveBlock(0) }); !p {
+		t.Error("expected panic for zero block size")
+	}
+}
+
+func TestRSVDRankForError(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{8, 4, 2, 1, 0.5, 0.25}
+	a := NewTestMatrix(30, 20, s, rnd)
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 6, withRand(rnd), RSVDAuto()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var total float64
+	for _, v := range s {
+		total += v * v
+	}
+	for _, test := range []struct {
+		eps  float64
+		want int
+	}{
+		{eps: 0, want: 6},
+		{eps: 0.24 / math.Sqrt(total), want: 6},
+		{eps: 0.26 / math.Sqrt(total), want: 5},
+		{eps: math.Sqrt(1+0.25+0.0625+0.01) / math.Sqrt(total), want: 3},
+		{eps: 1, want: 1},
+	} {
+		if got := rsvd.RankForError(test.eps); got != test.want {
+			t.Errorf("unexpected rank for eps=%v: got:%d want:%d", test.eps, got, test.want)
+		}
+	}
+
+	k := rsvd.RankForError(0.1)
+	rsvd.TruncateTo(k)
+	if rsvd.Rank() != k {
+		t.Errorf("unexpected rank after truncation: got:%d want:%d", rsvd.Rank(), k)
+	}
+	var u Dense
+	rsvd.UTo(&u)
+	if _, c := u.Dims(); c != k {
+		t.Errorf("unexpected number of columns of U after truncation: got:%d want:%d", c, k)
+	}
+	if got := rsvd.Values(nil); !floats.EqualApprox(got, s[:k], 1e-10) {
+		t.Errorf("unexpected singular values after truncation: got:%v want:%v", got, s[:k])
+	}
+	for _, k := range []int{0, k + 1} {
+		if p, _ := panics(func() { rsvd.TruncateTo(k) }); !p {
+			t.Errorf("expected panic for truncation to rank %d", k)
+		}
+	}
+	if p, _ := panics(func() { rsvd.RankForError(-1) }); !p {
+		t.Error("expected panic for negative eps")
+	}
+}
+
+func TestRSVDApplyInverseTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, rank = 25, 4
+	a := NewTestMatrix(n, n, []float64{20, 10, 5, 2, 0.5, 0.1}, rnd)
+	sym := NewSymDense(n, nil)
+	sym.SymOuterK(1, NewRandomNormalDense(n, rank, rnd))
+	b := NewRandomNormalDense(n, 3, rnd)
+
+	for _, test := range []struct {
+		a    Matrix
+		opts []RSVDOption
+	}{
+		{a: a},
+		{a: a, opts: []RSVDOption{RSVDRegularizer(0.3)}},
+		{a: sym, opts: []RSVDOption{RSVDRegularizer(1e-2)}},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(test.a, rank, append(test.opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		mu := rsvd.mu
+		if mu == 0 {
+			mu = rsvd.Values(nil)[rank-1]
+		}
+		u, sigma, v := rsvd.Factors()
+		var reg Dense
+		reg.Product(u, sigma, v.T())
+		for i := 0; i < n; i++ {
+			reg.Set(i, i, reg.At(i, i)+mu)
+		}
+		var want, got Dense
+		if err := want.Solve(&reg, b); err != nil {
+			t.Fatalf("unexpected error solving regularized system: %v", err)
+		}
+		rsvd.ApplyInverseTo(&got, b)
+		if !EqualApprox(&got, &want, 1e-10) {
+			t.Errorf("%T mu=%v: unexpected inverse applied", test.a, mu)
+		}
+	}
+
+	var rsvd RSVD
+	rsvd.Factorize(NewRandomNormalDense(n, n+2, rnd), rank, withRand(rnd))
+	if p, _ := panics(func() { rsvd.ApplyInverseTo(&Dense{}, b) }); !p {
+		t.Error("expected panic for non-square matrix")
+	}
+	if p, _ := panics(func() { RSVDRegularizer(0) }); !p {
+		t.Error("expected panic for zero regularizer")
+	}
+}
+
+func TestRSVDTheoreticalErrorBound(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	// A wide matrix is not transposed by RSVDAuto, so the sketch basis is retained.
+	const m, n, rank = 40, 60, 5
+	s := make([]float64, m)
+	for i := range s {
+		s[i] = math.Pow(0.7, float64(i))
+	}
+	a := NewTestMatrix(m, n, s, rnd)
+
+	for _, opts := range [][]RSVDOption{
+		{RSVDOrthoProjection(), RSVDAuto(), RSVDPowerIter(0)},
+		{RSVDOrthoProjection(), RSVDAuto()},
+	} {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, rank, append(opts, withRand(rnd))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		bound := rsvd.TheoreticalErrorBound(1e-3)
+
+		// Compute the spectral norm of the range error.
+		var r, qta Dense
+		qta.Mul(rsvd.q.T(), a)
+		r.Mul(rsvd.q, &qta)
+		r.Sub(a, &r)
+		var svd SVD
+		if !svd.Factorize(&r, SVDNone) {
+			t.Fatal("unexpected SVD failure")
+		}
+		if got := svd.Values(nil)[0]; got > bound {
+			t.Errorf("range error %v exceeds bound %v", got, bound)
+		}
+		if bound < s[rank] {
+			t.Errorf("bound %v less than best possible error %v", bound, s[rank])
+		}
+		if loose := rsvd.TheoreticalErrorBound(0.5); loose > bound {
+			t.Errorf("bound increased with failure probability: %v > %v", loose, bound)
+		}
+	}
+
+	var rsvd RSVD
+	rsvd.Factorize(a, rank, withRand(rnd))
+	if got := rsvd.TheoreticalErrorBound(0.01); !math.IsInf(got, 1) {
+		t.Errorf("unexpected bound without oversampling: %v", got)
+	}
+	rsvd.Factorize(a, m, withRand(rnd))
+	if got := rsvd.TheoreticalErrorBound(0.01); got != 0 {
+		t.Errorf("unexpected bound for deterministic factorization: %v", got)
+	}
+	for _, prob := range []float64{0, 1} {
+		if p, _ := panics(func() { rsvd.TheoreticalErrorBound(prob) }); !p {
+			t.Errorf("expected panic for failure probability %v", prob)
+		}
+	}
+}
+
+func TestRSVDFactorizeOp(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, k = 60, 40, 3
+	u := NewRandomNormalDense(m, k, rnd)
+	v := NewRandomNormalDense(n, k, rnd)
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = 1e-3 * rnd.NormFloat64()
+	}
+	op := NewLowRankPlusDiagOp(d, u, v)
+	var a Dense
+	a.Mul(u, v.T())
+	for i, di := range d {
+		a.set(i, i, a.at(i, i)+di)
+	}
+
+	for _, q := range []int{0, 2} {
+		var got, want RSVD
+		if !got.FactorizeOp(op, k, RSVDSource(rand.NewSource(2)), RSVDPowerIter(q)) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if !want.Factorize(&a, k, RSVDSource(rand.NewSource(2)), RSVDPowerIter(q)) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if r, c := got.Dims(); r != m || c != n {
+			t.Errorf("q=%d: unexpected dimensions: %d×%d", q, r, c)
+		}
+		if gv, wv := got.Values(nil), want.Values(nil); !floats.EqualApprox(gv, wv, 1e-10) {
+			t.Errorf("q=%d: unexpected singular values: got:%v want:%v", q, gv, wv)
+		}
+		var res Dense
+		got.ResidualTo(&res, &a)
+		if got, want := Norm(&res, 2), BestRankKError(&a, k); got > 10*want {
+			t.Errorf("q=%d: residual norm too large: got:%v best:%v", q, got, want)
+		}
+	}
+
+	var rsvd RSVD
+	for _, rank := range []int{0, n + 1} {
+		if p, _ := panics(func() { rsvd.FactorizeOp(op, rank) }); !p {
+			t.Errorf("expected panic for rank %d", rank)
+		}
+	}
+}
+
+func TestRSVDImportances(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, k = 30, 20, 4
+	a := NewTestMatrix(m, n, []float64{8, 4, 2, 1, 1e-8}, rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, k, withRand(rnd), RSVDPowerIter(2)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var approx Dense
+	u, sigma, v := rsvd.Factors()
+	approx.Product(u, sigma, v.T())
+
+	rows := rsvd.RowImportances()
+	if len(rows) != m {
+		t.Fatalf("unexpected number of row importances: got:%d want:%d", len(rows), m)
+	}
+	for i, got := range rows {
+		if want := Norm(approx.RowView(i), 2); math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected importance of row %d: got:%v want:%v", i, got, want)
+		}
+	}
+	cols := rsvd.ColumnImportances()
+	if len(cols) != n {
+		t.Fatalf("unexpected number of column importances: got:%d want:%d", len(cols), n)
+	}
+	for j, got := range cols {
+		if want := Norm(approx.ColView(j), 2); math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected importance of column %d: got:%v want:%v", j, got, want)
+		}
+	}
+	if got, want := floats.Dot(rows, rows), math.Pow(Norm(&approx, 2), 2); math.Abs(got-want) > 1e-10 {
+		t.Errorf("unexpected captured energy: got:%v want:%v", got, want)
+	}
+
+	// With standardized columns the row importances are those of the
+	// approximation of the scaled matrix, while V is in the coordinates
+	// of A, so the column importances remain those of A.
+	scale := NewDiagDense(n, nil)
+	for j := 0; j < n; j++ {
+		scale.SetDiag(j, float64(j+1))
+	}
+	var scaled Dense
+	scaled.Mul(a, scale)
+	if !rsvd.Factorize(&scaled, k, withRand(rnd), RSVDPowerIter(2), RSVDStandardize()) {
+		t.Fatal("unexpected factorization failure with standardization")
+	}
+	u, sigma, v = rsvd.Factors()
+	approx.Product(u, sigma, v.T())
+	s := rsvd.Values(nil)
+	rows = rsvd.RowImportances()
+	if got, want := floats.Dot(rows, rows), floats.Dot(s, s); math.Abs(got-want) > 1e-10*want {
+		t.Errorf("unexpected captured energy of scaled matrix: got:%v want:%v", got, want)
+	}
+	for j, got := range rsvd.ColumnImportances() {
+		if want := Norm(approx.ColView(j), 2); math.Abs(got-want) > 1e-10*want {
+			t.Errorf("unexpected importance of standardized column %d: got:%v want:%v", j, got, want)
+		}
+	}
+
+	var empty RSVD
+	if p, _ := panics(func() { empty.RowImportances() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+}
+
+func TestRSVDInnerGram(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, 20)
+	v := 1.0
+	for i := range s {
+		s[i] = v
+		v *= 0.5
+	}
+	a := NewTestMatrix(40, 300, s, rnd)
+	const k = 6
+	var got, want RSVD
+	if !got.Factorize(a, k, RSVDSource(rand.NewSource(2)), RSVDPowerIter(2), RSVDInnerGram()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if !want.Factorize(a, k, RSVDSource(rand.NewSource(2)), RSVDPowerIter(2)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if gv, wv := got.Values(nil), want.Values(nil); !floats.EqualApprox(gv, wv, 1e-12) {
+		t.Errorf("unexpected singular values: got:%v want:%v", gv, wv)
+	}
+	u, _, vf := got.Factors()
+	if !hasOrthonormalColumns(u, 1e-12) || !hasOrthonormalColumns(vf, 1e-10) {
+		t.Error("singular vectors not orthonormal")
+	}
+	var gotRes, wantRes Dense
+	got.ResidualTo(&gotRes, a)
+	want.ResidualTo(&wantRes, a)
+	if g, w := Norm(&gotRes, 2), Norm(&wantRes, 2); math.Abs(g-w) > 1e-10 {
+		t.Errorf("unexpected residual norm: got:%v want:%v", g, w)
+	}
+}
+
+func TestBestRankOne(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 30, 20
+	a := NewTestMatrix(m, n, []float64{5, 2, 1, 0.5}, rnd)
+	var svd SVD
+	if !svd.Factorize(a, SVDThin) {
+		t.Fatal("unexpected SVD failure")
+	}
+	var wantU, wantV Dense
+	svd.UTo(&wantU)
+	svd.VTo(&wantV)
+	uw := Col(nil, 0, &wantU)
+	vw := Col(nil, 0, &wantV)
+	var imax int
+	for i, x := range vw {
+		if math.Abs(x) > math.Abs(vw[imax]) {
+			imax = i
+		}
+	}
+	if vw[imax] < 0 {
+		floats.Scale(-1, uw)
+		floats.Scale(-1, vw)
+	}
+
+	sigma, u, v := BestRankOne(a, 30, rnd)
+	if math.Abs(sigma-5) > 1e-12 {
+		t.Errorf("unexpected singular value: got:%v want:5", sigma)
+	}
+	if !floats.EqualApprox(u, uw, 1e-10) {
+		t.Error("unexpected left singular vector")
+	}
+	if !floats.EqualApprox(v, vw, 1e-10) {
+		t.Error("unexpected right singular vector")
+	}
+
+	// A single step gives a lower bound of the largest singular value.
+	if sigma, _, _ := BestRankOne(a, 1, rnd); sigma > 5+1e-12 {
+		t.Errorf("singular value estimate above σ₁: %v", sigma)
+	}
+
+	sigma, u, v = BestRankOne(NewDense(3, 2, nil), 5, rnd)
+	if sigma != 0 || !floats.Equal(u, []float64{1, 0, 0}) || !floats.Equal(v, []float64{1, 0}) {
+		t.Errorf("unexpected result for zero matrix: %v %v %v", sigma, u, v)
+	}
+
+	if p, _ := panics(func() { BestRankOne(a, 0, rnd) }); !p {
+		t.Error("expected panic for zero iterations")
+	}
+}
+
+func TestRSVDReorder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewTestMatrix(20, 15, []float64{6, 4, 3, 2, 1}, rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 4, withRand(rnd), RSVDPowerIter(2)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	u, sigma, v := rsvd.Factors()
+	var want Dense
+	want.Product(u, sigma, v.T())
+	values := rsvd.Values(nil)
+
+	perm := []int{2, 0, 3, 1}
+	rsvd.Reorder(perm)
+	gotValues := rsvd.Values(nil)
+	pu, psigma, pv := rsvd.Factors()
+	for k, p := range perm {
+		if gotValues[k] != values[p] {
+			t.Errorf("unexpected singular value %d: got:%v want:%v", k, gotValues[k], values[p])
+		}
+		if !Equal(pu.ColView(k), u.ColView(p)) {
+			t.Errorf("unexpected left singular vector %d", k)
+		}
+		if !Equal(pv.ColView(k), v.ColView(p)) {
+			t.Errorf("unexpected right singular vector %d", k)
+		}
+	}
+	var got Dense
+	got.Product(pu, psigma, pv.T())
+	if !EqualApprox(&got, &want, 1e-12) {
+		t.Error("reordering changed the approximation")
+	}
+
+	for _, perm := range [][]int{{0, 1, 2}, {0, 1, 2, 2}, {0, 1, 2, 4}, {-1, 0, 1, 2}} {
+		if p, _ := panics(func() { rsvd.Reorder(perm) }); !p {
+			t.Errorf("expected panic for invalid permutation %v", perm)
+		}
+	}
+}
+
+func TestRSVDDeflatedOp(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{10, 8, 6, 4, 2, 1, 0.5, 0.25}
+	a := NewTestMatrix(40, 30, s, rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 3, withRand(rnd), RSVDPowerIter(3)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	op := rsvd.DeflatedOp(a)
+
+	var res Dense
+	rsvd.ResidualTo(&res, a)
+	for _, trans := range []bool{false, true} {
+		var want Matrix = &res
+		c := 30
+		if trans {
+			want = res.T()
+			c = 40
+		}
+		x := NewVecDense(c, nil)
+		for i := 0; i < c; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		var got, wantVec VecDense
+		op.MulVecTo(&got, trans, x)
+		wantVec.MulVec(want, x)
+		if !EqualApprox(&got, &wantVec, 1e-12) {
+			t.Errorf("trans=%t: unexpected product", trans)
+		}
+	}
+
+	// The deflated operator exposes the next block of the spectrum.
+	var next RSVD
+	if !next.FactorizeOp(op, 3, withRand(rnd), RSVDPowerIter(3)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := next.Values(nil); !floats.EqualApprox(got, s[3:6], 1e-3) {
+		t.Errorf("unexpected deflated singular values: got:%v want:%v", got, s[3:6])
+	}
+
+	if p, _ := panics(func() { rsvd.DeflatedOp(NewDense(30, 40, nil)) }); !p {
+		t.Error("expected panic for mismatched shape")
+	}
+}
+
+func TestRSVDLogger(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(12, 8, rnd)
+	for _, test := range []struct {
+		name string
+		a    Matrix
+		rank int
+		want []string
+	}{
+		{name: "none", a: a, rank: 3},
+		{name: "fallback", a: a, rank: 8, want: []string{`warning="fallback to deterministic SVD" rank=8 min_dim=8`}},
+		{name: "nan", a: NewDense(3, 3, []float64{1, math.NaN(), 0, 0, 1, 0, 0, 0, 1}), rank: 1, want: []string{`warning="NaN in input" rows=3 cols=3`}},
+	} {
+		var buf bytes.Buffer
+		var rsvd RSVD
+		rsvd.Factorize(test.a, test.rank, withRand(rnd), RSVDLogger(log.New(&buf, "", 0)))
+		got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if buf.Len() == 0 {
+			got = nil
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: unexpected number of warnings: got:%q want:%q", test.name, got, test.want)
+			continue
+		}
+		for i, w := range test.want {
+			if !strings.HasPrefix(got[i], "mat: rsvd ") || !strings.HasSuffix(got[i], w) {
+				t.Errorf("%s: unexpected warning: got:%q want:%q", test.name, got[i], w)
+			}
+		}
+	}
+}
+
+func TestRSVDCheckApprox(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{4, 2, 1, 0.5}
+	a := NewTestMatrix(20, 15, s, rnd)
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 2, withRand(rnd), RSVDPowerIter(3)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	want := math.Hypot(1, 0.5) / math.Sqrt(16+4+1+0.25)
+	if err := rsvd.CheckApprox(a, 1.01*want); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := rsvd.CheckApprox(a, 0.5*want)
+	aerr, ok := err.(*ApproxError)
+	if !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	if math.Abs(aerr.RelError-want) > 1e-2*want || aerr.Tol != 0.5*want {
+		t.Errorf("unexpected error fields: got:%+v want RelError:%v Tol:%v", aerr, want, 0.5*want)
+	}
+
+	if p, _ := panics(func() { rsvd.CheckApprox(a, -1) }); !p {
+		t.Error("expected panic for negative tolerance")
+	}
+}
+
+func TestRSVDOversample(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, 30)
+	for i := range s {
+		s[i] = 1 / float64(i+1)
+	}
+	a := NewTestMatrix(40, 30, s, rnd)
+	const k = 3
+
+	var plain, over RSVD
+	if !plain.Factorize(a, k, RSVDSource(rand.NewSource(2))) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if !over.Factorize(a, k, RSVDSource(rand.NewSource(2)), RSVDOversample(10)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := over.Rank(); got != k {
+		t.Errorf("unexpected rank: got:%d want:%d", got, k)
+	}
+	if !math.IsInf(plain.TheoreticalErrorBound(0.01), 1) || math.IsInf(over.TheoreticalErrorBound(0.01), 1) {
+		t.Error("oversampling not recorded")
+	}
+	var plainRes, overRes Dense
+	plain.ResidualTo(&plainRes, a)
+	over.ResidualTo(&overRes, a)
+	if Norm(&overRes, 2) >= Norm(&plainRes, 2) {
+		t.Errorf("oversampling did not reduce the error: got:%v without:%v", Norm(&overRes, 2), Norm(&plainRes, 2))
+	}
+
+	// An explicit oversampling overrides the choice of RSVDAuto.
+	var auto RSVD
+	if !auto.Factorize(NewTestMatrix(30, 40, s, rnd), k, withRand(rnd), RSVDOversample(4), RSVDAuto()) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if auto.oversample != 4 {
+		t.Errorf("unexpected oversampling with RSVDAuto: got:%d want:4", auto.oversample)
+	}
+
+	if p, _ := panics(func() { RSVDOversample(-1) }); !p {
+		t.Error("expected panic for negative oversampling")
+	}
+}
+
+func TestRSVDSketch(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{10, 5, 3, 2, 1e-3, 1e-4}
+	a := NewTestMatrix(40, 25, s, rnd)
+	op := NewMatrixOp(a)
+	for _, kind := range []SketchKind{SketchUniform, SketchGaussian, SketchRademacher, SketchSRHT} {
+		opts := []RSVDOption{withRand(rnd), RSVDSketch(kind), RSVDOversample(4)}
+		var rsvd, rop RSVD
+		if !rsvd.Factorize(a, 4, opts...) {
+			t.Fatalf("kind %d: unexpected factorization failure", kind)
+		}
+		if !rop.FactorizeOp(op, 4, opts...) {
+			t.Fatalf("kind %d: unexpected operator factorization failure", kind)
+		}
+		for _, f := range []*RSVD{&rsvd, &rop} {
+			if got := f.Values(nil); !floats.EqualApprox(got, s[:4], 1e-5) {
+				t.Errorf("kind %d: unexpected singular values: got:%v want:%v", kind, got, s[:4])
+			}
+		}
+	}
+
+	if p, _ := panics(func() { RSVDSketch(SketchSRHT + 1) }); !p {
+		t.Error("expected panic for unknown sketch kind")
+	}
+}
+
+func TestRSVDTolerance(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 60, 50
+	s := make([]float64, 30)
+	for i := range s {
+		s[i] = math.Pow(2, -float64(i)/2)
+	}
+	a := NewTestMatrix(m, n, s, rnd)
+	for _, tol := range []float64{0.5, 0.1, 1e-3} {
+		for _, block := range []int{1, 4} {
+			var rsvd RSVD
+			if !rsvd.Factorize(a, n, withRand(rnd), RSVDTolerance(tol), RSVDAdaptiveBlock(block)) {
+				t.Fatal("unexpected factorization failure")
+			}
+			// The smallest rank whose best approximation meets the tolerance;
+			// the randomized range may need a few more directions.
+			want := 1
+			for BestRankKError(a, want) > tol {
+				want++
+			}
+			got := rsvd.Rank()
+			if got < want || want+2 < got {
+				t.Errorf("tol=%v block=%d: unexpected rank: got:%d want:%d", tol, block, got, want)
+			}
+			var res Dense
+			rsvd.ResidualTo(&res, a)
+			if e := Norm(&res, 2); e > tol*(1+1e-12) {
+				t.Errorf("tol=%v block=%d: error %v exceeds tolerance", tol, block, e)
+			}
+		}
+	}
+
+	// The rank is bounded by the rank passed to Factorize.
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 5, withRand(rnd), RSVDTolerance(1e-6)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := rsvd.Rank(); got != 5 {
+		t.Errorf("unexpected rank at bound: got:%d want:5", got)
+	}
+
+	if !rsvd.Factorize(NewDense(m, n, nil), 5, withRand(rnd), RSVDTolerance(1e-6)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := rsvd.Values(nil); len(got) != 1 || got[0] != 0 {
+		t.Errorf("unexpected values for zero matrix: %v", got)
+	}
+
+	if p, _ := panics(func() { RSVDTolerance(-1) }); !p {
+		t.Error("expected panic for negative tolerance")
+	}
+}
+
+func TestRSVDErrEst(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, 40)
+	for i := range s {
+		s[i] = math.Pow(0.8, float64(i))
+	}
+	a := NewTestMatrix(60, 50, s, rnd)
+	for _, rank := range []int{2, 5, 10, 20} {
+		var rsvd RSVD
+		if !rsvd.Factorize(a, rank, withRand(rnd)) {
+			t.Fatal("unexpected factorization failure")
+		}
+		var res Dense
+		rsvd.ResidualTo(&res, a)
+		var svd SVD
+		if !svd.Factorize(&res, SVDNone) {
+			t.Fatal("unexpected SVD failure")
+		}
+		want := svd.Values(nil)[0]
+		got := rsvd.ErrEst(a, 5, rnd)
+		// The estimate bounds the error, overestimating by a modest factor.
+		if got < want || 20*want < got {
+			t.Errorf("rank=%d: unexpected error estimate: got:%v want:%v", rank, got, want)
+		}
+	}
+
+	var rsvd RSVD
+	if !rsvd.Factorize(a, 3, withRand(rnd)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	if p, _ := panics(func() { rsvd.ErrEst(a, 0, rnd) }); !p {
+		t.Error("expected panic for zero probes")
+	}
+	if p, _ := panics(func() { rsvd.ErrEst(a.T(), 2, rnd) }); !p {
+		t.Error("expected panic for mismatched matrix")
+	}
+}
+
+func TestRSVDShapes(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, dims := range [][2]int{{3, 10}, {10, 3}, {1, 5}, {5, 1}, {4, 4}} {
+		m, n := dims[0], dims[1]
+		a := NewRandomNormalDense(m, n, rnd)
+		for _, rank := range []int{1, 2, 3, 5, 12} {
+			for i, opts := range [][]RSVDOption{
+				nil,
+				{RSVDOversample(20)},
+				{RSVDPowerIter(2)},
+				{RSVDAuto()},
+				{RSVDPivotedSketch(1e-8)},
+				{RSVDSketch(SketchSRHT)},
+				{RSVDInnerGram()},
+				{RSVDTolerance(1e-3)},
+				{RSVDStandardize()},
+			} {
+				var rsvd RSVD
+				if !rsvd.Factorize(a, rank, append(opts, withRand(rnd))...) {
+					t.Fatalf("%d×%d rank=%d opts=%d: unexpected factorization failure", m, n, rank, i)
+				}
+				k := rsvd.Rank()
+				if k < 1 || min(min(m, n), rank) < k {
+					t.Errorf("%d×%d rank=%d opts=%d: unexpected rank %d", m, n, rank, i, k)
+				}
+				u, sigma, v := rsvd.Factors()
+				if r, c := u.Dims(); r != m || c != k {
+					t.Errorf("%d×%d rank=%d opts=%d: unexpected U size %d×%d", m, n, rank, i, r, c)
+				}
+				if r, c := sigma.Dims(); r != k || c != k {
+					t.Errorf("%d×%d rank=%d opts=%d: unexpected Σ size %d×%d", m, n, rank, i, r, c)
+				}
+				if r, c := v.Dims(); r != n || c != k {
+					t.Errorf("%d×%d rank=%d opts=%d: unexpected V size %d×%d", m, n, rank, i, r, c)
+				}
+			}
+		}
+	}
+
+	for _, a := range []Matrix{&Dense{}, NewDense(3, 4, nil).Slice(0, 0, 0, 4)} {
+		var rsvd RSVD
+		if p, _ := panics(func() { rsvd.Factorize(a, 1) }); !p {
+			t.Error("expected panic for matrix with zero dimension")
+		}
+	}
+}
+
+func TestRSVDVectors(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 40, 30, 5
+	a := NewTestMatrix(m, n, []float64{10, 8, 6, 4, 2, 1, 0.5, 0.1}, rnd)
+	sym := NewSymDense(n, nil)
+	sym.SymOuterK(1, NewTestMatrix(n, n, []float64{5, 4, 3, 2, 1, 0.1}, rnd))
+	for _, test := range []struct {
+		a    Matrix
+		rank int
+	}{
+		{a: a, rank: rank},
+		{a: a, rank: min(m, n)},
+		{a: sym, rank: rank},
+		{a: sym, rank: n},
+	} {
+		var full RSVD
+		if !full.Factorize(test.a, test.rank, RSVDSource(rand.NewSource(1))) {
+			t.Fatal("unexpected factorization failure")
+		}
+		for _, kind := range []SVDKind{SVDNone, SVDThinU, SVDThinV, SVDThin} {
+			var rsvd RSVD
+			if !rsvd.Factorize(test.a, test.rank, RSVDSource(rand.NewSource(1)), RSVDVectors(kind)) {
+				t.Fatal("unexpected factorization failure")
+			}
+			if got, want := rsvd.Values(nil), full.Values(nil); !floats.EqualApprox(got, want, 1e-10) {
+				t.Errorf("%T kind=%d: unexpected values: got:%v want:%v", test.a, kind, got, want)
+			}
+			if p, _ := panics(func() { rsvd.UTo(&Dense{}) }); p == (kind&SVDThinU != 0) {
+				t.Errorf("%T kind=%d: unexpected U availability", test.a, kind)
+			}
+			if p, _ := panics(func() { rsvd.VTo(&Dense{}) }); p == (kind&SVDThinV != 0) {
+				t.Errorf("%T kind=%d: unexpected V availability", test.a, kind)
+			}
+		}
+	}
+	for _, kind := range []SVDKind{SVDFullU, SVDFull} {
+		if p, _ := panics(func() { RSVDVectors(kind) }); !p {
+			t.Errorf("expected panic for kind %d", kind)
+		}
+	}
+}
+
+func TestRSVDFactorizeWith(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 40, 30, 4
+	a := NewTestMatrix(m, n, []float64{10, 8, 6, 4, 2, 1, 0.5, 0.1}, rnd)
+	for _, test := range []struct {
+		settings *RSVDSettings
+		opts     []RSVDOption
+	}{
+		{settings: nil, opts: nil},
+		{settings: &RSVDSettings{}, opts: nil},
+		{
+			settings: &RSVDSettings{Oversample: 5, PowerIter: 2, Sketch: SketchGaussian},
+			opts:     []RSVDOption{RSVDOversample(5), RSVDPowerIter(2), RSVDSketch(SketchGaussian)},
+		},
+		{
+			settings: &RSVDSettings{Sketch: SketchSRHT, SkipV: true},
+			opts:     []RSVDOption{RSVDSketch(SketchSRHT), RSVDSkipV()},
+		},
+		{
+			settings: &RSVDSettings{SkipU: true, SkipV: true},
+			opts:     []RSVDOption{RSVDVectors(SVDNone)},
+		},
+	} {
+		src := rand.NewSource(1)
+		if test.settings != nil {
+			test.settings.Src = src
+		}
+		var got, want RSVD
+		if !got.FactorizeWith(a, rank, test.settings) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if !want.Factorize(a, rank, append(test.opts, RSVDSource(rand.NewSource(1)))...) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if test.settings == nil {
+			// The global source was used, so the factors are not reproducible.
+			if got.Rank() != rank || got.Stats() != want.Stats() {
+				t.Errorf("unexpected factorization for nil settings")
+			}
+			continue
+		}
+		if !floats.Equal(got.Values(nil), want.Values(nil)) {
+			t.Errorf("settings %+v: unexpected values", *test.settings)
+		}
+		if got.hasU() != want.hasU() || got.hasV() != want.hasV() {
+			t.Errorf("settings %+v: unexpected singular vectors", *test.settings)
+		}
+		if got.Stats() != want.Stats() {
+			t.Errorf("settings %+v: unexpected stats: got:%+v want:%+v", *test.settings, got.Stats(), want.Stats())
+		}
+	}
+
+	var rsvd RSVD
+	if p, _ := panics(func() { rsvd.FactorizeWith(a, rank, &RSVDSettings{Oversample: -1}) }); !p {
+		t.Error("expected panic for negative oversampling")
+	}
+}
+
+func BenchmarkRSVDBanded(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	a := randBandDense(2000, 2000, 50, 50, rnd)
+	for i := 0; i < b.N; i++ {
+		var rsvd RSVD
+		rsvd.Factorize(a, 20)
+	}
+}
+
+func BenchmarkRSVDBandedAsDense(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	a := DenseCopyOf(randBandDense(2000, 2000, 50, 50, rnd))
+	for i := 0; i < b.N; i++ {
+		var rsvd RSVD
+		rsvd.Factorize(a, 20)
+	}
+}
+
+func BenchmarkAdaptiveRangeFinderBlock1(b *testing.B)  { adaptiveRangeFinderBench(b, 1) }
+func BenchmarkAdaptiveRangeFinderBlock10(b *testing.B) { adaptiveRangeFinderBench(b, 10) }
+
+// adaptiveRangeFinderBench benchmarks growing the basis of the range of a
+// matrix with 60 significant singular values by block columns at a time.
+func adaptiveRangeFinderBench(b *testing.B, block int) {
+	const m, n, rank = 1000, 500, 60
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, rank)
+	for i := range s {
+		s[i] = float64(rank - i)
+	}
+	a := NewTestMatrix(m, n, s, rnd)
+	tol := 1e-6 * Norm(a, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		adaptiveRangeFinder(a, tol, block, min(m, n), rnd)
+	}
+}
+
+func BenchmarkRSVDInnerSVD(b *testing.B)  { rsvdInnerBench(b, rsvdConfig{}) }
+func BenchmarkRSVDInnerGram(b *testing.B) { rsvdInnerBench(b, rsvdConfig{innerGram: true}) }
+
+// rsvdInnerBench benchmarks the inner decomposition of a short-wide
+// projection Y = Qᵀ·A.
+func rsvdInnerBench(b *testing.B, cfg rsvdConfig) {
+	const k, c = 20, 50000
+	rnd := rand.New(rand.NewSource(1))
+	y := NewRandomNormalDense(k, c, rnd)
+	rsvd := RSVD{svd: &SVD{}}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !rsvd.factorizeInner(y, cfg) {
+			b.Fatal("unexpected factorization failure")
+		}
+	}
+}
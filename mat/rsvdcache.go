@@ -0,0 +1,107 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"container/list"
+	"sync"
+)
+
+// RSVDCache is a cache of randomized singular value decompositions keyed by a
+// caller-chosen string identifying the factorized matrix. When the number of
+// entries exceeds the limit set by NewRSVDCache, the least recently used entry
+// is evicted. The zero value is an empty cache without a limit.
+//
+// An RSVDCache is safe for concurrent use by multiple goroutines.
+type RSVDCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	lru        list.List
+	entries    map[string]*list.Element
+}
+
+type rsvdCacheEntry struct {
+	key  string
+	rank int
+	rsvd *RSVD
+}
+
+// NewRSVDCache returns an empty RSVDCache that holds at most maxEntries
+// factorizations. If maxEntries is zero, the number of entries is not limited.
+// NewRSVDCache will panic if maxEntries is negative.
+func NewRSVDCache(maxEntries int) *RSVDCache {
+	if maxEntries < 0 {
+		panic("mat: negative cache size")
+	}
+	return &RSVDCache{maxEntries: maxEntries}
+}
+
+// GetOrFactorize returns the cached rank-rank factorization stored under key,
+// or computes the factorization of A with RSVD.Factorize, using the global
+// rand source, and stores it under key. A cached factorization with a
+// different rank is replaced. The caller is responsible for using a key that
+// identifies A; A is not compared with the matrix that was factorized.
+//
+// The returned RSVD is shared by all callers using the same key and must not
+// be modified, for example with TruncateTo, Reorder or Orthonormalize. The
+// factorization is computed without holding the cache lock, so concurrent
+// calls with the same uncached key may each factorize A, and all but one of
+// the results are discarded. GetOrFactorize returns nil, and does not store
+// an entry, if the factorization fails.
+func (c *RSVDCache) GetOrFactorize(key string, A Matrix, rank int) *RSVD {
+	if rsvd := c.get(key, rank); rsvd != nil {
+		return rsvd
+	}
+	rsvd := &RSVD{}
+	if !rsvd.Factorize(A, rank) {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*rsvdCacheEntry)
+		c.lru.MoveToFront(e)
+		if entry.rank == rank {
+			return entry.rsvd
+		}
+		entry.rank, entry.rsvd = rank, rsvd
+		return rsvd
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+	}
+	c.entries[key] = c.lru.PushFront(&rsvdCacheEntry{key: key, rank: rank, rsvd: rsvd})
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*rsvdCacheEntry).key)
+	}
+	return rsvd
+}
+
+// get returns the cached factorization stored under key if it has the given
+// rank, marking it as the most recently used, and nil otherwise.
+func (c *RSVDCache) get(key string, rank int) *RSVD {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := e.Value.(*rsvdCacheEntry)
+	if entry.rank != rank {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return entry.rsvd
+}
+
+// Len returns the number of factorizations held by the cache.
+func (c *RSVDCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,78 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestRSVDCache(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewRandomNormalDense(20, 10, rnd)
+	b := NewRandomNormalDense(15, 12, rnd)
+
+	c := NewRSVDCache(2)
+	ra := c.GetOrFactorize("a", a, 3)
+	if ra == nil {
+		t.Fatal("unexpected factorization failure")
+	}
+	if got := c.GetOrFactorize("a", a, 3); got != ra {
+		t.Error("cached factorization not reused")
+	}
+	if got := c.GetOrFactorize("a", a, 4); got == ra || got.Rank() != 4 {
+		t.Error("factorization with different rank not recomputed")
+	}
+	if c.Len() != 1 {
+		t.Errorf("unexpected number of entries: got:%d want:1", c.Len())
+	}
+
+	rb := c.GetOrFactorize("b", b, 2)
+	c.GetOrFactorize("a", a, 4)
+	// The least recently used entry, b, is evicted.
+	c.GetOrFactorize("c", a, 2)
+	if c.Len() != 2 {
+		t.Errorf("unexpected number of entries: got:%d want:2", c.Len())
+	}
+	if got := c.GetOrFactorize("b", b, 2); got == rb {
+		t.Error("evicted factorization reused")
+	}
+
+	if p, _ := panics(func() { NewRSVDCache(-1) }); !p {
+		t.Error("expected panic for negative cache size")
+	}
+}
+
+func TestRSVDCacheConcurrent(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	mats := make([]*Dense, 4)
+	for i := range mats {
+		mats[i] = NewRandomNormalDense(12, 8, rnd)
+	}
+
+	var c RSVDCache
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				k := (g + i) % len(mats)
+				if c.GetOrFactorize(strconv.Itoa(k), mats[k], 2) == nil {
+					t.Error("unexpected factorization failure")
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.Len() != len(mats) {
+		t.Errorf("unexpected number of entries: got:%d want:%d", c.Len(), len(mats))
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"io"
+
+	"golang.org/x/exp/rand"
+)
+
+// rsvdStreamOversample is the number of columns of the range sketch
+// used by RSVDStream in addition to the requested rank.
+const rsvdStreamOversample = 10
+
+// RSVDStream computes a single-pass randomized singular value decomposition
+// of a matrix that is supplied as a sequence of row blocks, so that the whole
+// matrix never needs to be held in memory. Each block A_i is used once, in
+// UpdateRows, and contributes to the range sketch Y = A Ω and the co-range
+// sketch W = Ψ A through
+//  Y_i = A_i Ω,  W += Ψ_i A_i,
+// where Ω is a cols×k and Ψ = [Ψ_1 … ] an l×m Gaussian matrix with
+// k = min(rank+10, cols) and l = 2k+1, and m is the number of rows supplied
+// so far. Finish then approximates A by Q X, where Q is an orthonormal basis
+// for the range of Y and X is the least-squares solution of (Ψ Q) X = W, and
+// forms the factorization from the SVD of X, following Tropp, Yurtsever, Udell
+// and Cevher, "Practical sketching algorithms for low-rank matrix
+// approximation", SIAM J. Matrix Anal. Appl. 38(4), 2017.
+//
+// Since A cannot be read again, the approximation is less accurate than that
+// of RSVD.Factorize, which projects A onto Q exactly. The memory used by the
+// sketches is O((m+cols)·rank).
+type RSVDStream struct {
+	cols, rank int
+	ks, l      int
+	rnd        *rand.Rand
+
+	omega *Dense
+	w     *Dense
+
+	// The rows of the range sketch Y and of Ψᵀ
+	// are accumulated as the rows of A are read.
+	y, psiT []float64
+	m       int
+}
+
+// NewRSVDStream returns a new RSVDStream for the rank-rank factorization of a
+// matrix with cols columns, using rnd as the source of randomness. If rnd is
+// nil, the global rand source is used. NewRSVDStream will panic if rank is not
+// in [1, cols].
+func NewRSVDStream(cols, rank int, rnd *rand.Rand) *RSVDStream {
+	if rank < 1 || cols < rank {
+		panic(ErrShape)
+	}
+	ks := min(rank+rsvdStreamOversample, cols)
+	l := 2*ks + 1
+	return &RSVDStream{
+		cols:  cols,
+		rank:  rank,
+		ks:    ks,
+		l:     l,
+		rnd:   rnd,
+		omega: makeRandomMatrix(cols, ks, distNormal, rnd),
+		w:     NewDense(l, cols, nil),
+	}
+}
+
+// Rows returns the number of rows supplied to the receiver so far.
+func (s *RSVDStream) Rows() int {
+	return s.m
+}
+
+// UpdateRows adds the rows of block to the sketches of the matrix. The block
+// is not retained, and may be reused by the caller after UpdateRows returns.
+// UpdateRows will panic if block does not have cols columns.
+func (s *RSVDStream) UpdateRows(block Matrix) {
+	rows, c := block.Dims()
+	if c != s.cols {
+		panic(ErrShape)
+	}
+	var yi Dense
+	yi.Mul(block, s.omega)
+	s.y = append(s.y, yi.mat.Data[:rows*s.ks]...)
+
+	psi := makeRandomMatrix(s.l, rows, distNormal, s.rnd)
+	var wi Dense
+	wi.Mul(psi, block)
+	s.w.Add(s.w, &wi)
+	var pt Dense
+	pt.CloneFrom(psi.T())
+	s.psiT = append(s.psiT, pt.mat.Data...)
+	s.m += rows
+}
+
+// Finish returns the factorization of the rows supplied so far. The receiver
+// is not modified, so further rows may be added and Finish called again.
+//
+// Finish returns ErrShape if fewer than rank rows have been supplied, and
+// ErrFailedSVD if the decomposition fails.
+func (s *RSVDStream) Finish() (*RSVD, error) {
+	m := s.m
+	if m < s.rank {
+		return nil, ErrShape
+	}
+
+	// [Q] = orth(Y) = m × k
+	k := min(s.ks, m)
+	Y := NewDense(m, s.ks, s.y[:m*s.ks:m*s.ks])
+	Q := orthonormalBasis(Y.Slice(0, m, 0, k).(*Dense))
+
+	// [X] = [(Ψ × Q)† × W] = k × cols
+	var psiQ Dense
+	psiQ.Mul(NewDense(m, s.l, s.psiT[:m*s.l:m*s.l]).T(), Q)
+	var qr QR
+	qr.Factorize(&psiQ)
+	var X Dense
+	if err := qr.SolveTo(&X, false, s.w); err != nil {
+		return nil, ErrFailedSVD
+	}
+
+	rsvd := &RSVD{svd: &SVD{}}
+	if !rsvd.svd.Factorize(&X, SVDThin) {
+		return nil, ErrFailedSVD
+	}
+	rsvd.m, rsvd.n = m, s.cols
+	rsvd.rank = k
+	rsvd.q = Q
+	rsvd.randomized = true
+	rsvd.recordSketch(s.rank)
+	rsvd.truncate(s.rank)
+	return rsvd, nil
+}
+
+// FactorizeStream computes a single-pass randomized singular value
+// decomposition of the m×cols matrix A whose rows are read from r, where m is
+// the total number of rows in the stream. The stream is a sequence of row
+// blocks, each encoded by Dense.WriteTo or Dense.MarshalBinaryTo with cols
+// columns, and it ends at the end of r. Only one block is held in memory at a
+// time, so the number of rows of A held in memory is bounded by the number of
+// rows of the largest block chosen by the writer. The blocks are passed to an
+// RSVDStream, which describes the sketches and their cost. If rnd is nil, the
+// global rand source is used.
+//
+// FactorizeStream returns an error if r does not hold a valid stream of
+// blocks, ErrShape if a block does not have cols columns or if the stream
+// holds fewer than rank rows, and ErrFailedSVD if the decomposition fails.
+// FactorizeStream will panic if rank is not in [1, cols].
+func FactorizeStream(r io.Reader, cols, rank int, rnd *rand.Rand) (*RSVD, error) {
+	s := NewRSVDStream(cols, rank, rnd)
+	var block Dense
+	for {
+		block.Reset()
+		n, err := block.ReadFrom(r)
+		if err != nil {
+			if n == 0 && err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, err
+		}
+		if _, c := block.Dims(); c != cols {
+			return nil, ErrShape
+		}
+		s.UpdateRows(&block)
+	}
+	return s.Finish()
+}
@@ -0,0 +1,173 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// writeRowBlocks writes the rows of a to w in blocks of at most size rows.
+func writeRowBlocks(t *testing.T, w io.Writer, a *Dense, size int) {
+	r, c := a.Dims()
+	for i := 0; i < r; i += size {
+		block := a.Slice(i, min(i+size, r), 0, c).(*Dense)
+		if _, err := block.WriteTo(w); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+}
+
+func TestFactorizeStream(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := make([]float64, 20)
+	v := 1.0
+	for i := range s {
+		s[i] = v
+		v *= 0.5
+	}
+	for _, test := range []struct {
+		m, n, rank, block int
+	}{
+		{m: 100, n: 40, rank: 4, block: 7},
+		{m: 60, n: 30, rank: 5, block: 60},
+		{m: 12, n: 30, rank: 5, block: 5},
+	} {
+		a := NewTestMatrix(test.m, test.n, s[:min(len(s), min(test.m, test.n))], rnd)
+		var buf bytes.Buffer
+		writeRowBlocks(t, &buf, a, test.block)
+
+		rsvd, err := FactorizeStream(&buf, test.n, test.rank, rnd)
+		if err != nil {
+			t.Fatalf("%d×%d: unexpected error: %v", test.m, test.n, err)
+		}
+		if r, c := rsvd.Dims(); r != test.m || c != test.n {
+			t.Errorf("%d×%d: unexpected dimensions: %d×%d", test.m, test.n, r, c)
+		}
+		if got := rsvd.Values(nil); !floats.EqualApprox(got, s[:test.rank], 1e-3) {
+			t.Errorf("%d×%d: unexpected singular values: got:%v want:%v", test.m, test.n, got, s[:test.rank])
+		}
+		u, _, v := rsvd.Factors()
+		if !hasOrthonormalColumns(u, 1e-12) || !hasOrthonormalColumns(v, 1e-12) {
+			t.Errorf("%d×%d: singular vectors not orthonormal", test.m, test.n)
+		}
+		var res Dense
+		rsvd.ResidualTo(&res, a)
+		if got, want := Norm(&res, 2), BestRankKError(a, test.rank); got > 1.1*want {
+			t.Errorf("%d×%d: residual norm too large: got:%v best:%v", test.m, test.n, got, want)
+		}
+	}
+
+	a := NewRandomNormalDense(6, 4, rnd)
+	for _, test := range []struct {
+		name string
+		cols int
+		rank int
+		data func() []byte
+		want error
+	}{
+		{
+			name: "columns",
+			cols: 5, rank: 2,
+			want: ErrShape,
+		},
+		{
+			name: "rows",
+			cols: 4, rank: 2,
+			data: func() []byte {
+				var buf bytes.Buffer
+				writeRowBlocks(t, &buf, a.Slice(0, 1, 0, 4).(*Dense), 1)
+				return buf.Bytes()
+			},
+			want: ErrShape,
+		},
+		{
+			name: "truncated",
+			cols: 4, rank: 2,
+			data: func() []byte {
+				var buf bytes.Buffer
+				writeRowBlocks(t, &buf, a, 3)
+				return buf.Bytes()[:buf.Len()-1]
+			},
+			want: io.ErrUnexpectedEOF,
+		},
+	} {
+		var data []byte
+		if test.data == nil {
+			var buf bytes.Buffer
+			writeRowBlocks(t, &buf, a, 3)
+			data = buf.Bytes()
+		} else {
+			data = test.data()
+		}
+		_, err := FactorizeStream(bytes.NewReader(data), test.cols, test.rank, rnd)
+		if err != test.want {
+			t.Errorf("%s: unexpected error: got:%v want:%v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestRSVDStream(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n, rank = 50, 20, 3
+	a := NewTestMatrix(m, n, []float64{8, 4, 2, 1, 0.5, 0.25}, rnd)
+	var buf bytes.Buffer
+	writeRowBlocks(t, &buf, a, 8)
+	want, err := FactorizeStream(&buf, n, rank, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := NewRSVDStream(n, rank, rand.New(rand.NewSource(1)))
+	if _, err := s.Finish(); err != ErrShape {
+		t.Errorf("unexpected error for empty stream: got:%v want:%v", err, ErrShape)
+	}
+	for i := 0; i < m; i += 8 {
+		// Blocks need not be Dense.
+		block := DenseCopyOf(a.Slice(i, min(i+8, m), 0, n).T())
+		s.UpdateRows(block.T())
+	}
+	if got := s.Rows(); got != m {
+		t.Errorf("unexpected number of rows: got:%d want:%d", got, m)
+	}
+	got, err := s.Finish()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(got.Values(nil), want.Values(nil), 1e-12) {
+		t.Errorf("unexpected singular values: got:%v want:%v", got.Values(nil), want.Values(nil))
+	}
+
+	// Finish does not consume the stream.
+	again, err := s.Finish()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.Equal(again.Values(nil), got.Values(nil)) {
+		t.Error("unexpected change after repeated Finish")
+	}
+	s.UpdateRows(a.Slice(0, 2, 0, n))
+	more, err := s.Finish()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, _ := more.Dims(); r != m+2 {
+		t.Errorf("unexpected rows after further update: got:%d want:%d", r, m+2)
+	}
+
+	if p, _ := panics(func() { s.UpdateRows(NewDense(2, n+1, nil)) }); !p {
+		t.Error("expected panic for wrong number of columns")
+	}
+	if p, _ := panics(func() { NewRSVDStream(n, n+1, rnd) }); !p {
+		t.Error("expected panic for rank greater than columns")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// srht holds the parameters of an n×k subsampled randomized Hadamard
+// transform
+//  Ω = √(N/k) D H R,
+// where N is the smallest power of two not less than n, D is the n×N matrix
+// holding random signs on its diagonal, H is the N×N Walsh–Hadamard matrix
+// normalized to be orthogonal, and R samples k of its N columns without
+// replacement.
+type srht struct {
+	n, size int
+	signs   []float64
+	cols    []int
+}
+
+// newSRHT returns an n×k subsampled randomized Hadamard transform drawn
+// from rnd, or the global rand source if rnd is nil.
+func newSRHT(n, k int, rnd *rand.Rand) srht {
+	size := 1
+	for size < n {
+		size *= 2
+	}
+	sign := rademacher(rnd)
+	signs := make([]float64, n)
+	for i := range signs {
+		signs[i] = sign()
+	}
+	perm := rand.Perm
+	if rnd != nil {
+		perm = rnd.Perm
+	}
+	return srht{n: n, size: size, signs: signs, cols: perm(size)[:k]}
+}
+
+// matrix returns the transform as an explicit n×k matrix. Its elements are
+// ±1/√k.
+func (t srht) matrix() *Dense {
+	k := len(t.cols)
+	f := 1 / math.Sqrt(float64(k))
+	p := NewDense(t.n, k, nil)
+	for i := 0; i < t.n; i++ {
+		row := p.rawRowView(i)
+		for j, c := range t.cols {
+			row[j] = t.signs[i] * hadamard(i, c) * f
+		}
+	}
+	return p
+}
+
+// sketch places the product a·Ω into dst, where the columns of a are divided
+// by the elements of scale if it is not nil. Each row of a is transformed by
+// the fast Walsh–Hadamard transform in O(N log N) operations, so the product
+// costs O(m·N·log N) rather than the O(m·n·k) of a general matrix multiply.
+func (t srht) sketch(dst *Dense, a Matrix, scale []float64) {
+	m, _ := a.Dims()
+	k := len(t.cols)
+	f := 1 / math.Sqrt(float64(k))
+	dst.reuseAsNonZeroed(m, k)
+	buf := make([]float64, t.size)
+	ad, isDense := a.(*Dense)
+	for i := 0; i < m; i++ {
+		if isDense {
+			copy(buf, ad.rawRowView(i))
+		} else {
+			for j := 0; j < t.n; j++ {
+				buf[j] = a.At(i, j)
+			}
+		}
+		for j, s := range t.signs {
+			buf[j] *= s
+		}
+		for j, s := range scale {
+			buf[j] /= s
+		}
+		for j := t.n; j < t.size; j++ {
+			buf[j] = 0
+		}
+		fwht(buf)
+		row := dst.rawRowView(i)
+		for j, c := range t.cols {
+			row[j] = buf[c] * f
+		}
+	}
+}
+
+// hadamard returns the element (-1)^popcount(i&j) of the
+// unnormalized Walsh–Hadamard matrix.
+func hadamard(i, j int) float64 {
+	x := i & j
+	h := 1.0
+	for ; x != 0; x &= x - 1 {
+		h = -h
+	}
+	return h
+}
+
+// fwht replaces x, whose length is a power of two, with its unnormalized
+// Walsh–Hadamard transform.
+func fwht(x []float64) {
+	n := len(x)
+	for h := 1; h < n; h *= 2 {
+		for i := 0; i < n; i += 2 * h {
+			for j := i; j < i+h; j++ {
+				x[j], x[j+h] = x[j]+x[j+h], x[j]-x[j+h]
+			}
+		}
+	}
+}
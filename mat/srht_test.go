@@ -0,0 +1,70 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSRHT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 5, n: 8, k: 3},
+		{m: 7, n: 13, k: 5},
+		{m: 3, n: 1, k: 1},
+	} {
+		tr := newSRHT(test.n, test.k, rnd)
+		p := tr.matrix()
+		if r, c := p.Dims(); r != test.n || c != test.k {
+			t.Fatalf("unexpected transform size: %d×%d", r, c)
+		}
+
+		a := NewRandomNormalDense(test.m, test.n, rnd)
+		scale := make([]float64, test.n)
+		for i := range scale {
+			scale[i] = 1 + rnd.Float64()
+		}
+		for _, s := range [][]float64{nil, scale} {
+			ps := DenseCopyOf(p)
+			for i, v := range s {
+				row := ps.rawRowView(i)
+				for j := range row {
+					row[j] /= v
+				}
+			}
+			var want, got Dense
+			want.Mul(a, ps)
+			tr.sketch(&got, a, s)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("%d×%d: fast sketch does not match explicit transform", test.m, test.n)
+			}
+			// The fast path for Dense matches the generic path.
+			got.Reset()
+			tr.sketch(&got, a.T().T(), s)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("%d×%d: generic sketch does not match explicit transform", test.m, test.n)
+			}
+		}
+	}
+
+	// Without padding the columns of the transform are orthogonal
+	// with squared norm N/k.
+	const n, k = 16, 6
+	p := newSRHT(n, k, rnd).matrix()
+	var g Dense
+	g.Mul(p.T(), p)
+	want := NewDiagDense(k, nil)
+	for i := 0; i < k; i++ {
+		want.SetDiag(i, float64(n)/k)
+	}
+	if !EqualApprox(&g, want, 1e-12) {
+		t.Errorf("unexpected Gram matrix of transform:\n%v", Formatted(&g))
+	}
+}
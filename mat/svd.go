@@ -5,6 +5,9 @@
 package mat
 
 import (
+	"math"
+	"sort"
+
 	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/lapack"
 	"gonum.org/v1/gonum/lapack/lapack64"
@@ -138,6 +141,135 @@ func (svd *SVD) Factorize(a Matrix, kind SVDKind) (ok bool) {
 	return ok
 }
 
+// factorizeJacobi computes the thin singular value decomposition of the k×n
+// matrix a, with k <= n, using one-sided Jacobi rotations on the rows of a.
+// The one-sided Jacobi method computes small singular values of matrices
+// with graded rows to high relative accuracy, but is considerably slower
+// than the bidiagonalization used by Factorize for all but small k.
+//
+// factorizeJacobi returns whether the iteration converged.
+func (svd *SVD) factorizeJacobi(a *Dense) (ok bool) {
+	const maxSweeps = 60
+
+	svd.s = svd.s[:0]
+	k, n := a.Dims()
+	if n < k {
+		panic(ErrShape)
+	}
+	b := DenseCopyOf(a)
+	q := NewDense(k, k, nil)
+	for i := 0; i < k; i++ {
+		q.set(i, i, 1)
+	}
+
+	// dlamchE is the machine epsilon.
+	const dlamchE = 1.0 / (1 << 53)
+	tol := float64(n) * dlamchE
+	for sweep := 0; ; sweep++ {
+		if sweep == maxSweeps {
+			svd.kind = 0
+			return false
+		}
+		rotated := false
+		for p := 0; p < k-1; p++ {
+			bp := b.rawRowView(p)
+			for r := p + 1; r < k; r++ {
+				br := b.rawRowView(r)
+				alpha := blas64.Dot(blas64.Vector{N: n, Inc: 1, Data: bp}, blas64.Vector{N: n, Inc: 1, Data: bp})
+				beta := blas64.Dot(blas64.Vector{N: n, Inc: 1, Data: br}, blas64.Vector{N: n, Inc: 1, Data: br})
+				gamma := blas64.Dot(blas64.Vector{N: n, Inc: 1, Data: bp}, blas64.Vector{N: n, Inc: 1, Data: br})
+				if gamma == 0 || math.Abs(gamma) <= tol*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for j := range bp {
+					bp[j], br[j] = c*bp[j]-s*br[j], s*bp[j]+c*br[j]
+				}
+				for i := 0; i < k; i++ {
+					row := q.rawRowView(i)
+					row[p], row[r] = c*row[p]-s*row[r], s*row[p]+c*row[r]
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	// The rows of b are now orthogonal with norms equal to the
+	// singular values. Order them by decreasing norm.
+	sv := make([]float64, k)
+	for i := range sv {
+		sv[i] = blas64.Nrm2(blas64.Vector{N: n, Inc: 1, Data: b.rawRowView(i)})
+	}
+	perm := make([]int, k)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool { return sv[perm[i]] > sv[perm[j]] })
+
+	u := NewDense(k, k, nil)
+	vt := NewDense(k, n, nil)
+	svd.s = use(svd.s, k)
+	for i, pi := range perm {
+		svd.s[i] = sv[pi]
+		for j := 0; j < k; j++ {
+			u.set(j, i, q.at(j, pi))
+		}
+		row := vt.rawRowView(i)
+		if sv[pi] == 0 {
+			continue
+		}
+		for j, v := range b.rawRowView(pi) {
+			row[j] = v / sv[pi]
+		}
+	}
+	completeOrthonormalRows(vt, svd.s)
+
+	svd.kind = SVDThin
+	svd.u = u.mat
+	svd.vt = vt.mat
+	return true
+}
+
+// completeOrthonormalRows replaces the rows of a for which the corresponding
+// value of s is zero with unit vectors orthogonal to all other rows of a.
+// The rows of a with non-zero s must be orthonormal.
+func completeOrthonormalRows(a *Dense, s []float64) {
+	k, n := a.Dims()
+	for i := 0; i < k; i++ {
+		if s[i] != 0 {
+			continue
+		}
+		row := a.rawRowView(i)
+		// Orthogonalize canonical vectors against the existing
+		// rows until one with a significant remainder is found.
+		for e := 0; e < n; e++ {
+			zero(row)
+			row[e] = 1
+			for pass := 0; pass < 2; pass++ {
+				for j := 0; j < k; j++ {
+					if j == i || (s[j] == 0 && j > i) {
+						continue
+					}
+					other := a.rawRowView(j)
+					d := blas64.Dot(blas64.Vector{N: n, Inc: 1, Data: row}, blas64.Vector{N: n, Inc: 1, Data: other})
+					blas64.Axpy(-d, blas64.Vector{N: n, Inc: 1, Data: other}, blas64.Vector{N: n, Inc: 1, Data: row})
+				}
+			}
+			norm := blas64.Nrm2(blas64.Vector{N: n, Inc: 1, Data: row})
+			if norm > 0.5 {
+				blas64.Scal(1/norm, blas64.Vector{N: n, Inc: 1, Data: row})
+				break
+			}
+		}
+	}
+}
+
 // Kind returns the SVDKind of the decomposition. If no decomposition has been
 // computed, Kind returns -1.
 func (svd *SVD) Kind() SVDKind {
@@ -228,7 +360,7 @@ func (svd *SVD) VTo(dst *Dense) {
 		panic(badFact)
 	}
 	kind := svd.kind
-	if kind&SVDThinU == 0 && kind&SVDFullV == 0 {
+	if kind&SVDThinV == 0 && kind&SVDFullV == 0 {
 		panic("svd: v not computed during factorization")
 	}
 	r := svd.vt.Rows
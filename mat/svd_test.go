@@ -5,6 +5,7 @@
 package mat
 
 import (
+	"math"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -170,6 +171,35 @@ func TestSVD(t *testing.T) {
 	}
 }
 
+func TestSVDThinVOnly(t *testing.T) {
+	t.Parallel()
+	const m, n = 5, 3
+	a := NewDense(m, n, []float64{
+		1, 2, 0,
+		0, 1, 3,
+		2, 0, 1,
+		1, 1, 1,
+		3, 0, 2,
+	})
+	var svd SVD
+	if !svd.Factorize(a, SVDThinV) {
+		t.Fatal("unexpected factorization failure")
+	}
+	var v Dense
+	svd.VTo(&v)
+	if r, c := v.Dims(); r != n || c != n {
+		t.Fatalf("unexpected dimensions of V: got:%d×%d want:%d×%d", r, c, n, n)
+	}
+	var vtv Dense
+	vtv.Mul(v.T(), &v)
+	if !EqualApprox(&vtv, eye(n), 1e-14) {
+		t.Error("V does not have orthonormal columns")
+	}
+	if p, _ := panics(func() { svd.UTo(&Dense{}) }); !p {
+		t.Error("expected panic for U not computed")
+	}
+}
+
 func extractSVD(svd *SVD) (s []float64, u, v *Dense) {
 	u = &Dense{}
 	svd.UTo(u)
@@ -177,3 +207,75 @@ func extractSVD(svd *SVD) (s []float64, u, v *Dense) {
 	svd.VTo(v)
 	return svd.Values(nil), u, v
 }
+
+func TestSVDFactorizeJacobi(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		k, n int
+	}{
+		{k: 1, n: 5},
+		{k: 3, n: 3},
+		{k: 5, n: 20},
+		{k: 10, n: 40},
+	} {
+		a := NewDense(test.k, test.n, nil)
+		for i := range a.mat.Data {
+			a.mat.Data[i] = rnd.NormFloat64()
+		}
+		var svd SVD
+		if !svd.factorizeJacobi(a) {
+			t.Fatalf("unexpected Jacobi failure for %d×%d", test.k, test.n)
+		}
+		var want SVD
+		want.Factorize(a, SVDNone)
+		s := svd.Values(nil)
+		if !floats.EqualApprox(s, want.Values(nil), 1e-12) {
+			t.Errorf("unexpected singular values for %d×%d:\ngot: %v\nwant:%v", test.k, test.n, s, want.Values(nil))
+		}
+		var u, v, got Dense
+		svd.UTo(&u)
+		svd.VTo(&v)
+		got.Product(&u, NewDiagDense(test.k, s), v.T())
+		if !EqualApprox(&got, a, 1e-12) {
+			t.Errorf("unexpected reconstruction for %d×%d", test.k, test.n)
+		}
+		if !isOrthonormal(&u, 1e-12) {
+			t.Errorf("U not orthonormal for %d×%d", test.k, test.n)
+		}
+	}
+
+	// Rows graded over many orders of magnitude, with a
+	// zero row, must keep full relative accuracy.
+	const k, n = 4, 6
+	g := NewDense(n, n, nil)
+	for i := range g.mat.Data {
+		g.mat.Data[i] = rnd.NormFloat64()
+	}
+	var qr QR
+	qr.Factorize(g)
+	var qt Dense
+	qr.QTo(&qt)
+	want := []float64{1, 1e-10, 1e-20, 0}
+	a := NewDense(k, n, nil)
+	for i := 0; i < k; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, want[i]*qt.At(j, i))
+		}
+	}
+	var svd SVD
+	if !svd.factorizeJacobi(a) {
+		t.Fatal("unexpected Jacobi failure for graded matrix")
+	}
+	for i, s := range svd.Values(nil) {
+		if math.Abs(s-want[i]) > 1e-14*want[i] {
+			t.Errorf("unexpected singular value %d: got:%v want:%v", i, s, want[i])
+		}
+	}
+	var v, vtv Dense
+	svd.VTo(&v)
+	vtv.Mul(v.T(), &v)
+	if !EqualApprox(&vtv, eye(k), 1e-12) {
+		t.Error("V not orthonormal for graded matrix")
+	}
+}
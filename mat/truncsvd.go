@@ -0,0 +1,174 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// TruncatedSVD is a type for creating and using the deterministic truncated
+// singular value decomposition of a matrix,
+//  A ≈ U Σ Vᵀ,
+// where U is m×rank and V is n×rank with orthonormal columns, and Σ is the
+// rank×rank diagonal matrix of the largest singular values of A. It is the
+// best rank-rank approximation of A.
+//
+// TruncatedSVD provides the accessors of RSVD, so that the deterministic and
+// the randomized decompositions can be used interchangeably. It is preferable
+// to RSVD for small matrices, or when rank is close to min(m,n), where the
+// randomized sketch does not reduce the cost and only adds variance.
+type TruncatedSVD struct {
+	svd  SVD
+	rank int
+	m, n int
+}
+
+// Factorize computes the rank-rank truncated singular value decomposition of
+// A. A is reduced to bidiagonal form by the Golub–Kahan Householder
+// bidiagonalization, the thin SVD is computed from the bidiagonal matrix, and
+// the factors are truncated to the leading rank singular triplets. The cost is
+// that of the thin SVD of A.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+// Factorize will panic if rank is not in [1, min(m,n)].
+func (t *TruncatedSVD) Factorize(A Matrix, rank int) (ok bool) {
+	m, n := A.Dims()
+	if rank < 1 || min(m, n) < rank {
+		panic(ErrShape)
+	}
+	t.rank, t.m, t.n = 0, 0, 0
+	if !t.svd.Factorize(A, SVDThin) {
+		return false
+	}
+	t.svd.s = t.svd.s[:rank]
+	t.svd.u.Cols = rank
+	t.svd.vt.Rows = rank
+	t.rank, t.m, t.n = rank, m, n
+	return true
+}
+
+func (t *TruncatedSVD) succFact() bool {
+	return t.rank != 0
+}
+
+// Dims returns the dimensions of the factorized matrix A.
+//
+// Dims will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Dims() (m, n int) {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	return t.m, t.n
+}
+
+// Rank returns the number of singular values and vectors of the factorization.
+//
+// Rank will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Rank() int {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	return t.rank
+}
+
+// Values returns the rank largest singular values of the factorized matrix in
+// descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length rank, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Values(s []float64) []float64 {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	return t.svd.Values(s)
+}
+
+// UTo extracts the m×rank matrix U of left singular vectors from the truncated
+// singular value decomposition.
+//
+// If dst is empty, UTo will resize dst to be m×rank. When dst is non-empty, then
+// UTo will panic if dst is not the appropriate size. UTo will also panic if
+// the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) UTo(dst *Dense) {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	t.svd.UTo(dst)
+}
+
+// VTo extracts the n×rank matrix V of right singular vectors from the
+// truncated singular value decomposition.
+//
+// If dst is empty, VTo will resize dst to be n×rank. When dst is non-empty, then
+// VTo will panic if dst is not the appropriate size. VTo will also panic if
+// the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) VTo(dst *Dense) {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	t.svd.VTo(dst)
+}
+
+// Factors returns newly allocated matrices holding the factors of the
+// decomposition A ≈ U Σ Vᵀ, where u is m×rank, sigma is the rank×rank
+// diagonal matrix of singular values in descending order and v is n×rank.
+//
+// Factors will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Factors() (u, sigma, v *Dense) {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	u, v = &Dense{}, &Dense{}
+	t.UTo(u)
+	t.VTo(v)
+	sigma = NewDense(t.rank, t.rank, nil)
+	for i, s := range t.svd.s {
+		sigma.set(i, i, s)
+	}
+	return u, sigma, v
+}
+
+// ResidualTo places the residual A - U Σ Vᵀ of the approximation of A into
+// dst. A must be the matrix that was factorized.
+//
+// If dst is empty, ResidualTo will resize dst to be m×n. When dst is
+// non-empty, ResidualTo will panic if dst is not m×n. ResidualTo will also
+// panic if A is not m×n or if the receiver does not contain a successful
+// factorization.
+func (t *TruncatedSVD) ResidualTo(dst *Dense, A Matrix) {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	m, n := A.Dims()
+	if m != t.m || n != t.n {
+		panic(ErrShape)
+	}
+	u, sigma, v := t.Factors()
+	dst.reuseAsNonZeroed(m, n)
+	dst.Product(u, sigma, v.T())
+	dst.Sub(A, dst)
+}
+
+// ToSVD returns an SVD of kind SVDThin holding the factors of the truncated
+// decomposition. The returned SVD does not share storage with the receiver.
+//
+// ToSVD will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) ToSVD() *SVD {
+	if !t.succFact() {
+		panic(badFact)
+	}
+	var u, v Dense
+	t.UTo(&u)
+	t.VTo(&v)
+	var vt Dense
+	vt.CloneFrom(v.T())
+	return &SVD{
+		kind: SVDThin,
+		s:    t.Values(nil),
+		u:    u.mat,
+		vt:   vt.mat,
+	}
+}
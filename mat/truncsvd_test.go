@@ -0,0 +1,74 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestTruncatedSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	s := []float64{10, 5, 4, 2, 1, 0.5}
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 10, n: 6, rank: 3},
+		{m: 6, n: 10, rank: 5},
+		{m: 8, n: 6, rank: 6},
+	} {
+		a := NewTestMatrix(test.m, test.n, s, rnd)
+		var tsvd TruncatedSVD
+		if !tsvd.Factorize(a, test.rank) {
+			t.Fatal("unexpected factorization failure")
+		}
+		if got := tsvd.Rank(); got != test.rank {
+			t.Errorf("unexpected rank: got:%d want:%d", got, test.rank)
+		}
+		if got := tsvd.Values(nil); !floats.EqualApprox(got, s[:test.rank], 1e-12) {
+			t.Errorf("unexpected singular values: got:%v want:%v", got, s[:test.rank])
+		}
+		u, _, v := tsvd.Factors()
+		if r, c := u.Dims(); r != test.m || c != test.rank {
+			t.Errorf("unexpected U size: %d×%d", r, c)
+		}
+		if r, c := v.Dims(); r != test.n || c != test.rank {
+			t.Errorf("unexpected V size: %d×%d", r, c)
+		}
+		if !hasOrthonormalColumns(u, 1e-12) || !hasOrthonormalColumns(v, 1e-12) {
+			t.Error("singular vectors not orthonormal")
+		}
+
+		// The truncated decomposition is the best rank approximation.
+		var res Dense
+		tsvd.ResidualTo(&res, a)
+		if got, want := Norm(&res, 2), BestRankKError(a, test.rank); math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected residual norm: got:%v want:%v", got, want)
+		}
+
+		var fromSVD Dense
+		svd := tsvd.ToSVD()
+		svd.UTo(&fromSVD)
+		if !Equal(&fromSVD, u) {
+			t.Error("unexpected U from ToSVD")
+		}
+	}
+
+	a := NewRandomNormalDense(5, 4, rnd)
+	var tsvd TruncatedSVD
+	for _, rank := range []int{0, 5} {
+		if p, _ := panics(func() { tsvd.Factorize(a, rank) }); !p {
+			t.Errorf("expected panic for rank %d", rank)
+		}
+	}
+	if p, _ := panics(func() { tsvd.Rank() }); !p {
+		t.Error("expected panic for unfactorized receiver")
+	}
+}